@@ -1,22 +1,32 @@
 package commandhandler
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/bwmarrin/discordgo"
 )
 
 func DefaultOptionsTypeMap() map[OptionType]discordgo.ApplicationCommandOptionType {
 	return map[OptionType]discordgo.ApplicationCommandOptionType{
-		StringOptionType:  discordgo.ApplicationCommandOptionString,
-		IntegerOptionType: discordgo.ApplicationCommandOptionInteger,
-		FloatOptionType:   discordgo.ApplicationCommandOptionNumber,
-		BooleanOptionType: discordgo.ApplicationCommandOptionBoolean,
-		UserOptionType:    discordgo.ApplicationCommandOptionUser,
-		MemberOptionType:  discordgo.ApplicationCommandOptionUser,
-		ChannelOptionType: discordgo.ApplicationCommandOptionChannel,
-		RoleOptionType:    discordgo.ApplicationCommandOptionRole,
+		StringOptionType:      discordgo.ApplicationCommandOptionString,
+		IntegerOptionType:     discordgo.ApplicationCommandOptionInteger,
+		FloatOptionType:       discordgo.ApplicationCommandOptionNumber,
+		BooleanOptionType:     discordgo.ApplicationCommandOptionBoolean,
+		UserOptionType:        discordgo.ApplicationCommandOptionUser,
+		MemberOptionType:      discordgo.ApplicationCommandOptionUser,
+		ChannelOptionType:     discordgo.ApplicationCommandOptionChannel,
+		RoleOptionType:        discordgo.ApplicationCommandOptionRole,
+		AttachmentOptionType:  discordgo.ApplicationCommandOptionAttachment,
+		MentionableOptionType: discordgo.ApplicationCommandOptionMentionable,
 	}
 }
 
+const defaultVariadicOptionCount = 10
+
 type Builder interface {
 	Build(cmd Command) *discordgo.ApplicationCommand
 }
@@ -31,16 +41,20 @@ type SimpleBuilder struct {
 
 func (b SimpleBuilder) buildOption(opt Option) *discordgo.ApplicationCommandOption {
 	o := discordgo.ApplicationCommandOption{
-		Name:        opt.Name,
-		Description: opt.Description,
-		Type:        b.OptionsTypeMap[opt.Type],
-		Required:    opt.Required,
+		Name:                     opt.Name,
+		NameLocalizations:        opt.NameLocalizations,
+		Description:              opt.Description,
+		DescriptionLocalizations: opt.DescriptionLocalizations,
+		Type:                     b.OptionsTypeMap[opt.Type],
+		Required:                 opt.Required,
+		Autocomplete:             opt.Autocomplete != nil,
 	}
 
 	for _, c := range opt.Choices {
 		o.Choices = append(o.Choices, &discordgo.ApplicationCommandOptionChoice{
-			Name:  c.Name,
-			Value: c.Value,
+			Name:              c.Name,
+			NameLocalizations: c.NameLocalizations,
+			Value:             c.Value,
 		})
 	}
 
@@ -67,71 +81,285 @@ func (b SimpleBuilder) buildOption(opt Option) *discordgo.ApplicationCommandOpti
 	return &o
 }
 
+func (b SimpleBuilder) buildVariadicOption(opt Option) []*discordgo.ApplicationCommandOption {
+	count, required := defaultVariadicOptionCount, 0
+	if opt.Required {
+		required = 1
+	}
+
+	for _, rule := range opt.Rules {
+		switch r := rule.(type) {
+		case MaxItems:
+			count = r.Max
+		case MinItems:
+			required = max(required, r.Min)
+		}
+	}
+
+	options := []*discordgo.ApplicationCommandOption{}
+	for n := 1; n <= count; n++ {
+		o := b.buildOption(opt)
+		o.Name = fmt.Sprintf("%s%d", opt.Name, n)
+		o.Required = n <= required
+		options = append(options, o)
+	}
+
+	return options
+}
+
+func (b SimpleBuilder) buildOptions(opts []Option) []*discordgo.ApplicationCommandOption {
+	options := []*discordgo.ApplicationCommandOption{}
+
+	for _, opt := range opts {
+		if opt.Variadic {
+			options = append(options, b.buildVariadicOption(opt)...)
+		} else {
+			options = append(options, b.buildOption(opt))
+		}
+	}
+
+	return options
+}
+
+func (b SimpleBuilder) buildSubs(subs []Command) []*discordgo.ApplicationCommandOption {
+	options := []*discordgo.ApplicationCommandOption{}
+
+	for _, sub := range subs {
+		option := &discordgo.ApplicationCommandOption{
+			Type:                     discordgo.ApplicationCommandOptionSubCommand,
+			Name:                     sub.Name,
+			NameLocalizations:        sub.NameLocalizations,
+			Description:              sub.Description,
+			DescriptionLocalizations: sub.DescriptionLocalizations,
+		}
+
+		if len(sub.Subs) > 0 {
+			option.Type = discordgo.ApplicationCommandOptionSubCommandGroup
+			option.Options = b.buildSubs(sub.Subs)
+		} else {
+			option.Options = b.buildOptions(sub.Options)
+		}
+
+		options = append(options, option)
+	}
+
+	return options
+}
+
 func (b SimpleBuilder) Build(cmd Command) *discordgo.ApplicationCommand {
 	command := discordgo.ApplicationCommand{
 		Name:        cmd.Name,
 		Description: cmd.Description,
+		Type:        discordgo.ChatApplicationCommand,
+	}
+
+	if len(cmd.NameLocalizations) > 0 {
+		command.NameLocalizations = &cmd.NameLocalizations
+	}
+
+	if len(cmd.DescriptionLocalizations) > 0 {
+		command.DescriptionLocalizations = &cmd.DescriptionLocalizations
+	}
+
+	if cmd.Permissions != 0 {
+		command.DefaultMemberPermissions = &cmd.Permissions
+	}
+
+	switch cmd.Kind {
+	case UserCommandKind:
+		command.Type = discordgo.UserApplicationCommand
+		command.Description = ""
+		command.DescriptionLocalizations = nil
+		return &command
+	case MessageCommandKind:
+		command.Type = discordgo.MessageApplicationCommand
+		command.Description = ""
+		command.DescriptionLocalizations = nil
+		return &command
 	}
 
 	if len(cmd.Subs) > 0 {
-		if len(cmd.Subs[0].Subs) > 0 {
-			subs := []*discordgo.ApplicationCommandOption{}
-
-			for _, sub := range cmd.Subs {
-				subsOfSub := []*discordgo.ApplicationCommandOption{}
-
-				for _, subOfSub := range sub.Subs {
-					opts := []*discordgo.ApplicationCommandOption{}
-					for _, opt := range subOfSub.Options {
-						opts = append(opts, b.buildOption(opt))
-					}
-
-					subsOfSub = append(subsOfSub, &discordgo.ApplicationCommandOption{
-						Type:        discordgo.ApplicationCommandOptionSubCommand,
-						Name:        subOfSub.Name,
-						Description: subOfSub.Description,
-						Options:     opts,
-					})
-				}
-
-				subs = append(subs, &discordgo.ApplicationCommandOption{
-					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
-					Name:        sub.Name,
-					Description: sub.Description,
-					Options:     subsOfSub,
-				})
-			}
+		command.Options = b.buildSubs(cmd.Subs)
+	} else {
+		command.Options = b.buildOptions(cmd.Options)
+	}
 
-			command.Options = subs
-		} else {
-			subs := []*discordgo.ApplicationCommandOption{}
+	return &command
+}
+
+var commandNameRegex = regexp.MustCompile(`^[-_\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+type ValidationError struct {
+	Path string
+	Err  error
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (b SimpleBuilder) BuildStrict(cmd Command) (*discordgo.ApplicationCommand, error) {
+	if errs := b.Validate(cmd); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return b.Build(cmd), nil
+}
 
-			for _, sub := range cmd.Subs {
-				opts := []*discordgo.ApplicationCommandOption{}
+func (b SimpleBuilder) Validate(cmd Command) []error {
+	errs := []error{}
+	report := func(path string, format string, args ...any) {
+		errs = append(errs, ValidationError{path, fmt.Errorf(format, args...)})
+	}
+
+	if cmd.Kind != ChatInputCommandKind {
+		if l := utf8.RuneCountInString(cmd.Name); l < 1 || l > 32 {
+			report(cmd.Name, "name must be between 1 and 32 characters long")
+		}
+		if len(cmd.Subs) > 0 || len(cmd.Options) > 0 {
+			report(cmd.Name, "context menu commands cannot have subcommands or options")
+		}
+		return errs
+	}
+
+	b.validateCommand(cmd, cmd.Name, 1, report)
+	return errs
+}
+
+func (b SimpleBuilder) validateCommand(cmd Command, path string, depth int, report func(path string, format string, args ...any)) {
+	validateLocalizations(cmd.Name, cmd.NameLocalizations, cmd.Description, cmd.DescriptionLocalizations, path, report)
+
+	if depth > 3 {
+		report(path, "subcommands cannot be nested deeper than 3 levels")
+	}
+
+	if len(cmd.Subs) > 0 && len(cmd.Options) > 0 {
+		report(path, "a command cannot have both subcommands and options")
+	}
+
+	if len(cmd.Subs) > 25 {
+		report(path, "a command cannot have more than 25 subcommands")
+	}
+
+	names := map[string]bool{}
+	for _, sub := range cmd.Subs {
+		if names[sub.Name] {
+			report(path, "duplicate subcommand name '%s'", sub.Name)
+		}
+		names[sub.Name] = true
+
+		b.validateCommand(sub, path+" > "+sub.Name, depth+1, report)
+	}
+
+	count, optional := 0, false
+	for i, opt := range cmd.Options {
+		optPath := path + "." + opt.Name
+
+		if names[opt.Name] {
+			report(path, "duplicate option name '%s'", opt.Name)
+		}
+		names[opt.Name] = true
+
+		b.validateOption(opt, optPath, report)
+
+		if opt.Rest && i != len(cmd.Options)-1 {
+			report(optPath, "an option that consumes the rest of the message must be the last option")
+		}
+
+		if opt.Variadic && i != len(cmd.Options)-1 {
+			report(optPath, "a variadic option must be the last option")
+		}
+
+		if opt.Rest && opt.Variadic {
+			report(optPath, "an option cannot be both rest and variadic")
+		}
 
-				for _, opt := range sub.Options {
-					opts = append(opts, b.buildOption(opt))
-				}
+		// Variadic options are checked as the numbered options they expand to.
+		expanded := []*discordgo.ApplicationCommandOption{{Name: opt.Name, Required: opt.Required}}
+		if opt.Variadic {
+			expanded = b.buildVariadicOption(opt)
+		}
 
-				subs = append(subs, &discordgo.ApplicationCommandOption{
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-					Name:        sub.Name,
-					Description: sub.Description,
-					Options:     opts,
-				})
+		for _, o := range expanded {
+			if o.Name != opt.Name && utf8.RuneCountInString(o.Name) > 32 {
+				report(optPath, "numbered option name '%s' is longer than 32 characters", o.Name)
 			}
+			if o.Required && optional {
+				report(optPath, "required options must be placed before optional options")
+				break
+			}
+			optional = optional || !o.Required
+		}
+		count += len(expanded)
+	}
+
+	if count > 25 {
+		report(path, "a command cannot have more than 25 options")
+	}
+}
+
+func (b SimpleBuilder) validateOption(opt Option, path string, report func(path string, format string, args ...any)) {
+	validateLocalizations(opt.Name, opt.NameLocalizations, opt.Description, opt.DescriptionLocalizations, path, report)
+
+	if len(opt.Choices) > 25 {
+		report(path, "an option cannot have more than 25 choices")
+	}
+
+	if len(opt.Choices) > 0 && opt.Autocomplete != nil {
+		report(path, "an option cannot have both choices and autocomplete")
+	}
+
+	for i, c := range opt.Choices {
+		choicePath := fmt.Sprintf("%s.choices[%d]", path, i)
 
-			command.Options = subs
+		if l := utf8.RuneCountInString(c.Name); l < 1 || l > 100 {
+			report(choicePath, "name must be between 1 and 100 characters long")
 		}
-	} else {
-		opts := []*discordgo.ApplicationCommandOption{}
 
-		for _, opt := range cmd.Options {
-			opts = append(opts, b.buildOption(opt))
+		var ok bool
+		switch b.OptionsTypeMap[opt.Type] {
+		case discordgo.ApplicationCommandOptionString:
+			_, ok = c.Value.(string)
+		case discordgo.ApplicationCommandOptionInteger:
+			_, ok = c.Value.(int64)
+		case discordgo.ApplicationCommandOptionNumber:
+			_, ok = c.Value.(float64)
+		default:
+			report(choicePath, "choices are not supported for this option type")
+			continue
+		}
+		if !ok {
+			report(choicePath, "value of type %T does not match the option type", c.Value)
 		}
+	}
+}
 
-		command.Options = opts
+func validateLocalizations(name string, names map[discordgo.Locale]string, description string, descriptions map[discordgo.Locale]string, path string, report func(path string, format string, args ...any)) {
+	validateName(name, path, report)
+	validateDescription(description, path, report)
+
+	for locale, name := range names {
+		validateName(name, fmt.Sprintf("%s[%s]", path, locale), report)
 	}
 
-	return &command
+	for locale, description := range descriptions {
+		validateDescription(description, fmt.Sprintf("%s[%s]", path, locale), report)
+	}
+}
+
+func validateName(name string, path string, report func(path string, format string, args ...any)) {
+	if !commandNameRegex.MatchString(name) {
+		report(path, "name must be 1-32 characters long and contain no spaces or symbols")
+	} else if name != strings.ToLower(name) {
+		report(path, "name must be lowercase")
+	}
+}
+
+func validateDescription(description string, path string, report func(path string, format string, args ...any)) {
+	if l := utf8.RuneCountInString(description); l < 1 || l > 100 {
+		report(path, "description must be between 1 and 100 characters long")
+	}
 }
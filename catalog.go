@@ -0,0 +1,85 @@
+package commandhandler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type RuleError struct {
+	Kind   string
+	Params map[string]any
+}
+
+func (e RuleError) Error() string {
+	return renderMessage(DefaultRuleMessages[e.Kind], e.Params)
+}
+
+var DefaultRuleMessages = map[string]string{
+	"max_int":      "value exceeds the maximum allowed of {max}",
+	"max_float":    "value exceeds the maximum allowed of {max}",
+	"max_string":   "string length exceeds the maximum allowed of {max}",
+	"min_int":      "value is less than the minimum allowed of {min}",
+	"min_float":    "value is less than the minimum allowed of {min}",
+	"min_string":   "string length is less than the minimum allowed of {min}",
+	"uppercase":    "value must be uppercase",
+	"lowercase":    "value must be lowercase",
+	"channel_type": "channel type '{type}' is not allowed",
+	"min_items":    "number of values is less than the minimum allowed of {min}",
+	"max_items":    "number of values exceeds the maximum allowed of {max}",
+}
+
+type MessageCatalog interface {
+	Message(locale discordgo.Locale, err RuleError) (string, bool)
+}
+
+type MapCatalog map[discordgo.Locale]map[string]string
+
+func (c MapCatalog) Message(locale discordgo.Locale, err RuleError) (string, bool) {
+	template, ok := c[locale][err.Kind]
+	if !ok {
+		return "", false
+	}
+	return renderMessage(template, err.Params), true
+}
+
+func renderMessage(template string, params map[string]any) string {
+	for name, value := range params {
+		var v string
+		switch value := value.(type) {
+		case float64:
+			v = fmt.Sprintf("%.2f", value)
+		default:
+			v = fmt.Sprint(value)
+		}
+		template = strings.ReplaceAll(template, "{"+name+"}", v)
+	}
+	return template
+}
+
+type localizedError struct {
+	message string
+	err     error
+}
+
+func (e localizedError) Error() string { return e.message }
+
+func (e localizedError) Unwrap() error { return e.err }
+
+func LocalizeError(ctx Context, catalog MessageCatalog, err HandlerError) HandlerError {
+	var ruleErr RuleError
+	if catalog == nil || !errors.As(err.Err, &ruleErr) {
+		return err
+	}
+
+	for _, locale := range []discordgo.Locale{ctx.Locale(), ctx.GuildLocale()} {
+		if message, ok := catalog.Message(locale, ruleErr); ok {
+			err.Err = localizedError{message, err.Err}
+			return err
+		}
+	}
+
+	return err
+}
@@ -1,10 +1,37 @@
 package commandhandler
 
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+type CommandKind uint8
+
+const (
+	ChatInputCommandKind CommandKind = 0
+	UserCommandKind      CommandKind = 1
+	MessageCommandKind   CommandKind = 2
+)
+
+const (
+	TargetUserOption    = "user"
+	TargetMemberOption  = "member"
+	TargetMessageOption = "message"
+)
+
 type Command struct {
+	Kind        CommandKind
 	Name        string
 	Description string
+
+	NameLocalizations        map[discordgo.Locale]string
+	DescriptionLocalizations map[discordgo.Locale]string
+
 	Aliases     []string
 	Subs        []Command
 	Options     []Option
+	Permissions int64
+	Middlewares []Middleware
+	Cooldown    Cooldown
 	Run         func(ctx Context, opts map[string]any)
+	RunE        func(ctx Context, opts map[string]any) error
 }
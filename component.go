@@ -0,0 +1,163 @@
+package commandhandler
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type ComponentHandler func(ctx *ComponentContext, params map[string]string) error
+
+// defaultComponentTTL matches the lifetime of an interaction token, after
+// which a message-bound route can usually no longer be answered anyway.
+const defaultComponentTTL = 15 * time.Minute
+
+type componentRoute struct {
+	pattern   *regexp.Regexp
+	params    []string
+	handler   ComponentHandler
+	messageID string
+	expires   time.Time
+}
+
+type ComponentRouter struct {
+	mu     sync.Mutex
+	routes []componentRoute
+}
+
+func NewComponentRouter() *ComponentRouter {
+	return &ComponentRouter{}
+}
+
+func (r *ComponentRouter) Handle(pattern string, handler ComponentHandler) {
+	re, params := compileComponentPattern(pattern)
+	r.add(componentRoute{pattern: re, params: params, handler: handler})
+}
+
+// HandleMessage registers a route that only matches components on the given
+// message and expires after ttl, or defaultComponentTTL when ttl is not set.
+func (r *ComponentRouter) HandleMessage(messageID string, pattern string, ttl time.Duration, handler ComponentHandler) {
+	if ttl <= 0 {
+		ttl = defaultComponentTTL
+	}
+
+	re, params := compileComponentPattern(pattern)
+	r.add(componentRoute{pattern: re, params: params, handler: handler, messageID: messageID, expires: time.Now().Add(ttl)})
+}
+
+func (r *ComponentRouter) add(route componentRoute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route)
+}
+
+func (r *ComponentRouter) match(messageID string, customID string) (ComponentHandler, map[string]string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.routes = slices.DeleteFunc(r.routes, func(route componentRoute) bool {
+		return !route.expires.IsZero() && now.After(route.expires)
+	})
+
+	for _, route := range r.routes {
+		if route.messageID != "" && route.messageID != messageID {
+			continue
+		}
+
+		match := route.pattern.FindStringSubmatch(customID)
+		if match == nil {
+			continue
+		}
+
+		params := map[string]string{}
+		for i, name := range route.params {
+			params[name] = match[i+1]
+		}
+		return route.handler, params, true
+	}
+
+	return nil, nil, false
+}
+
+// compileComponentPattern turns {name} placeholders into capture groups and
+// returns the placeholder names in group order. Names are kept out of the
+// expression so they may contain any character.
+func compileComponentPattern(pattern string) (*regexp.Regexp, []string) {
+	params := []string{}
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for {
+		start := strings.IndexByte(pattern, '{')
+		end := strings.IndexByte(pattern, '}')
+		if start < 0 || end < start {
+			break
+		}
+		expr.WriteString(regexp.QuoteMeta(pattern[:start]))
+		expr.WriteString("(.+?)")
+		params = append(params, pattern[start+1:end])
+		pattern = pattern[end+1:]
+	}
+
+	expr.WriteString(regexp.QuoteMeta(pattern))
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()), params
+}
+
+type ComponentContext struct {
+	SlashCommandContext
+}
+
+func (ctx ComponentContext) CustomID() string { return ctx.i.MessageComponentData().CustomID }
+
+func (ctx ComponentContext) Values() []string { return ctx.i.MessageComponentData().Values }
+
+func (ctx ComponentContext) Message() *discordgo.Message { return ctx.i.Message }
+
+func (ctx ComponentContext) Update(opts ReplyOptions) error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	if ctx.state.status == notResponded {
+		err := ctx.s.InteractionRespond(ctx.i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: opts.interactionResponseData(),
+		})
+		if err != nil {
+			return err
+		}
+	} else if _, err := ctx.s.InteractionResponseEdit(ctx.i, opts.webhookEdit()); err != nil {
+		return err
+	}
+
+	ctx.state.status = responded
+	return nil
+}
+
+func (ctx ComponentContext) DeferUpdate() error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	if ctx.state.status != notResponded {
+		return nil
+	}
+
+	err := ctx.s.InteractionRespond(ctx.i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx.state.status = deferred
+	return nil
+}
+
+func ComponentToContext(s *discordgo.Session, i *discordgo.InteractionCreate) *ComponentContext {
+	return &ComponentContext{SlashCommandContext{s, i.Interaction, &responseState{}}}
+}
@@ -0,0 +1,54 @@
+package commandhandler
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestComponentRouterMatch(t *testing.T) {
+	r := NewComponentRouter()
+	r.Handle("vote:{id}:{choice}", func(*ComponentContext, map[string]string) error { return nil })
+	r.Handle("profile:{user-id}", func(*ComponentContext, map[string]string) error { return nil })
+	r.Handle("a.b+{x}", func(*ComponentContext, map[string]string) error { return nil })
+
+	tests := []struct {
+		customID string
+		want     map[string]string
+	}{
+		{"vote:42:yes", map[string]string{"id": "42", "choice": "yes"}},
+		{"profile:123", map[string]string{"user-id": "123"}},
+		{"a.b+c", map[string]string{"x": "c"}},
+		{"aXb+c", nil},
+		{"vote:42", nil},
+	}
+
+	for _, tt := range tests {
+		_, params, ok := r.match("", tt.customID)
+		if ok != (tt.want != nil) || !maps.Equal(params, tt.want) {
+			t.Errorf("match(%q) = %v, %v, want %v", tt.customID, params, ok, tt.want)
+		}
+	}
+}
+
+func TestComponentRouterMessageTTL(t *testing.T) {
+	r := NewComponentRouter()
+	r.HandleMessage("1", "confirm", 0, func(*ComponentContext, map[string]string) error { return nil })
+
+	if _, _, ok := r.match("2", "confirm"); ok {
+		t.Error("route matched a different message")
+	}
+	if _, _, ok := r.match("1", "confirm"); !ok {
+		t.Error("route did not match its message")
+	}
+
+	r.routes[0].expires = time.Now().Add(-time.Second)
+	if _, _, ok := r.match("1", "confirm"); ok || len(r.routes) != 0 {
+		t.Error("expired route was not removed")
+	}
+
+	r.HandleMessage("1", "confirm", 0, func(*ComponentContext, map[string]string) error { return nil })
+	if r.routes[0].expires.IsZero() {
+		t.Error("route without ttl never expires")
+	}
+}
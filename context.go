@@ -1,6 +1,8 @@
 package commandhandler
 
 import (
+	"sync"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -9,12 +11,103 @@ type Context interface {
 	GuildId() string
 	ChannelId() string
 	Member() *discordgo.Member
+	User() *discordgo.User
+	Locale() discordgo.Locale
+	GuildLocale() discordgo.Locale
 	Reply(content string) error
+	ReplyEphemeral(content string) error
+	ReplyWith(opts ReplyOptions) error
+	Defer(ephemeral bool) error
+	FollowUp(content string) error
+	EditReply(content string) error
+	DeleteReply() error
+}
+
+type ReplyOptions struct {
+	Content         string
+	Embeds          []*discordgo.MessageEmbed
+	Files           []*discordgo.File
+	Components      []discordgo.MessageComponent
+	TTS             bool
+	AllowedMentions *discordgo.MessageAllowedMentions
+	Ephemeral       bool
+}
+
+func (opts ReplyOptions) flags() discordgo.MessageFlags {
+	if opts.Ephemeral {
+		return discordgo.MessageFlagsEphemeral
+	}
+	return 0
+}
+
+func (opts ReplyOptions) messageSend() *discordgo.MessageSend {
+	return &discordgo.MessageSend{
+		Content:         opts.Content,
+		Embeds:          opts.Embeds,
+		Files:           opts.Files,
+		Components:      opts.Components,
+		TTS:             opts.TTS,
+		AllowedMentions: opts.AllowedMentions,
+	}
+}
+
+func (opts ReplyOptions) interactionResponseData() *discordgo.InteractionResponseData {
+	return &discordgo.InteractionResponseData{
+		Content:         opts.Content,
+		Embeds:          opts.Embeds,
+		Files:           opts.Files,
+		Components:      opts.Components,
+		TTS:             opts.TTS,
+		AllowedMentions: opts.AllowedMentions,
+		Flags:           opts.flags(),
+	}
+}
+
+func (opts ReplyOptions) webhookParams() *discordgo.WebhookParams {
+	return &discordgo.WebhookParams{
+		Content:         opts.Content,
+		Embeds:          opts.Embeds,
+		Files:           opts.Files,
+		Components:      opts.Components,
+		TTS:             opts.TTS,
+		AllowedMentions: opts.AllowedMentions,
+		Flags:           opts.flags(),
+	}
+}
+
+func (opts ReplyOptions) webhookEdit() *discordgo.WebhookEdit {
+	edit := &discordgo.WebhookEdit{
+		Content:         &opts.Content,
+		Files:           opts.Files,
+		AllowedMentions: opts.AllowedMentions,
+	}
+	if len(opts.Embeds) > 0 {
+		edit.Embeds = &opts.Embeds
+	}
+	if len(opts.Components) > 0 {
+		edit.Components = &opts.Components
+	}
+	return edit
+}
+
+type responseStatus uint8
+
+const (
+	notResponded responseStatus = iota
+	deferred
+	responded
+)
+
+type responseState struct {
+	mu      sync.Mutex
+	status  responseStatus
+	message *discordgo.Message
 }
 
 type MessageContext struct {
-	s *discordgo.Session
-	m *discordgo.Message
+	s     *discordgo.Session
+	m     *discordgo.Message
+	state *responseState
 }
 
 func (ctx MessageContext) Session() *discordgo.Session { return ctx.s }
@@ -25,16 +118,90 @@ func (ctx MessageContext) ChannelId() string { return ctx.m.ChannelID }
 
 func (ctx MessageContext) Member() *discordgo.Member { return ctx.m.Member }
 
+func (ctx MessageContext) User() *discordgo.User { return ctx.m.Author }
+
+func (ctx MessageContext) Locale() discordgo.Locale { return ctx.GuildLocale() }
+
+func (ctx MessageContext) GuildLocale() discordgo.Locale {
+	if guild, err := ctx.s.State.Guild(ctx.GuildId()); err == nil {
+		return discordgo.Locale(guild.PreferredLocale)
+	}
+	return ""
+}
+
 func (ctx MessageContext) Reply(content string) error {
+	return ctx.ReplyWith(ReplyOptions{Content: content})
+}
+
+func (ctx MessageContext) ReplyEphemeral(content string) error {
+	return ctx.ReplyWith(ReplyOptions{Content: content, Ephemeral: true})
+}
+
+func (ctx MessageContext) ReplyWith(opts ReplyOptions) error {
+	data := opts.messageSend()
+
+	if opts.Ephemeral && ctx.GuildId() != "" {
+		channel, err := ctx.s.UserChannelCreate(ctx.m.Author.ID)
+		if err != nil {
+			return err
+		}
+		_, err = ctx.s.ChannelMessageSendComplex(channel.ID, data)
+		return err
+	}
+
+	data.Reference = ctx.m.Reference()
+	msg, err := ctx.s.ChannelMessageSendComplex(ctx.ChannelId(), data)
+	if err != nil {
+		return err
+	}
+
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+	if ctx.state.message == nil {
+		ctx.state.message = msg
+	}
+	return nil
+}
+
+func (ctx MessageContext) Defer(ephemeral bool) error {
+	return ctx.s.ChannelTyping(ctx.ChannelId())
+}
+
+func (ctx MessageContext) FollowUp(content string) error {
 	_, err := ctx.s.ChannelMessageSendReply(ctx.ChannelId(), content, ctx.m.Reference())
 	return err
 }
 
+func (ctx MessageContext) EditReply(content string) error {
+	ctx.state.mu.Lock()
+	msg := ctx.state.message
+	ctx.state.mu.Unlock()
+
+	if msg == nil {
+		return NoReplyError
+	}
+	_, err := ctx.s.ChannelMessageEdit(msg.ChannelID, msg.ID, content)
+	return err
+}
+
+func (ctx MessageContext) DeleteReply() error {
+	ctx.state.mu.Lock()
+	msg := ctx.state.message
+	ctx.state.message = nil
+	ctx.state.mu.Unlock()
+
+	if msg == nil {
+		return NoReplyError
+	}
+	return ctx.s.ChannelMessageDelete(msg.ChannelID, msg.ID)
+}
+
 func (ctx MessageContext) Message() *discordgo.Message { return ctx.m }
 
 type SlashCommandContext struct {
-	s *discordgo.Session
-	i *discordgo.Interaction
+	s     *discordgo.Session
+	i     *discordgo.Interaction
+	state *responseState
 }
 
 func (ctx SlashCommandContext) Session() *discordgo.Session { return ctx.s }
@@ -45,21 +212,152 @@ func (ctx SlashCommandContext) ChannelId() string { return ctx.i.ChannelID }
 
 func (ctx SlashCommandContext) Member() *discordgo.Member { return ctx.i.Member }
 
+func (ctx SlashCommandContext) User() *discordgo.User {
+	if ctx.i.Member != nil {
+		return ctx.i.Member.User
+	}
+	return ctx.i.User
+}
+
+func (ctx SlashCommandContext) Locale() discordgo.Locale { return ctx.i.Locale }
+
+func (ctx SlashCommandContext) GuildLocale() discordgo.Locale {
+	if ctx.i.GuildLocale != nil {
+		return *ctx.i.GuildLocale
+	}
+	return ""
+}
+
 func (ctx SlashCommandContext) Reply(content string) error {
-	return ctx.s.InteractionRespond(ctx.i, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+	return ctx.ReplyWith(ReplyOptions{Content: content})
+}
+
+func (ctx SlashCommandContext) ReplyEphemeral(content string) error {
+	return ctx.ReplyWith(ReplyOptions{Content: content, Ephemeral: true})
+}
+
+func (ctx SlashCommandContext) ReplyWith(opts ReplyOptions) error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	switch ctx.state.status {
+	case deferred:
+		if _, err := ctx.s.InteractionResponseEdit(ctx.i, opts.webhookEdit()); err != nil {
+			return err
+		}
+	case responded:
+		_, err := ctx.s.FollowupMessageCreate(ctx.i, true, opts.webhookParams())
+		return err
+	default:
+		err := ctx.s.InteractionRespond(ctx.i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: opts.interactionResponseData(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx.state.status = responded
+	return nil
+}
+
+func (ctx SlashCommandContext) Defer(ephemeral bool) error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	if ctx.state.status != notResponded {
+		return nil
+	}
+
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	err := ctx.s.InteractionRespond(ctx.i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: content,
+			Flags: flags,
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	ctx.state.status = deferred
+	return nil
+}
+
+func (ctx SlashCommandContext) FollowUp(content string) error {
+	ctx.state.mu.Lock()
+	status := ctx.state.status
+	ctx.state.mu.Unlock()
+
+	if status != responded {
+		return ctx.Reply(content)
+	}
+
+	_, err := ctx.s.FollowupMessageCreate(ctx.i, true, &discordgo.WebhookParams{Content: content})
+	return err
+}
+
+func (ctx SlashCommandContext) EditReply(content string) error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	if ctx.state.status == notResponded {
+		return NoReplyError
+	}
+
+	if _, err := ctx.s.InteractionResponseEdit(ctx.i, &discordgo.WebhookEdit{Content: &content}); err != nil {
+		return err
+	}
+
+	ctx.state.status = responded
+	return nil
+}
+
+func (ctx SlashCommandContext) DeleteReply() error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	if ctx.state.status == notResponded {
+		return NoReplyError
+	}
+	return ctx.s.InteractionResponseDelete(ctx.i)
 }
 
 func (ctx SlashCommandContext) Interaction() *discordgo.Interaction { return ctx.i }
 
+// ChannelPermissions returns the invoking member's permissions in the given
+// channel. Only the invoking channel's permissions come from the interaction
+// payload. discordgo drops the permissions Discord sends with resolved channel
+// options, so for any other channel this is a state lookup with a REST
+// fallback, which fails in DMs and for uncached guilds the bot cannot fetch.
+func (ctx SlashCommandContext) ChannelPermissions(channelID string) (int64, error) {
+	if ctx.i.Member == nil {
+		return 0, NotInGuildError
+	}
+	if channelID == ctx.i.ChannelID {
+		return ctx.i.Member.Permissions, nil
+	}
+
+	p, err := ctx.s.State.MessagePermissions(&discordgo.Message{
+		ChannelID: channelID,
+		Author:    ctx.i.Member.User,
+		Member:    ctx.i.Member,
+	})
+	if err != nil {
+		return ctx.s.UserChannelPermissions(ctx.i.Member.User.ID, channelID)
+	}
+	return p, nil
+}
+
 func MessageToContext(s *discordgo.Session, m *discordgo.Message) Context {
-	return &MessageContext{s, m}
+	return &MessageContext{s, m, &responseState{}}
 }
 
 func SlashCommandToContext(s *discordgo.Session, i *discordgo.InteractionCreate) Context {
-	return &SlashCommandContext{s, i.Interaction}
+	return &SlashCommandContext{s, i.Interaction, &responseState{}}
 }
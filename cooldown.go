@@ -0,0 +1,95 @@
+package commandhandler
+
+import (
+	"sync"
+	"time"
+)
+
+type CooldownScope uint8
+
+const (
+	UserCooldownScope    CooldownScope = 0
+	MemberCooldownScope  CooldownScope = 1
+	ChannelCooldownScope CooldownScope = 2
+	GuildCooldownScope   CooldownScope = 3
+	GlobalCooldownScope  CooldownScope = 4
+)
+
+type Cooldown struct {
+	Duration time.Duration
+	Scope    CooldownScope
+	Burst    int
+}
+
+func (c Cooldown) key(ctx Context) string {
+	switch c.Scope {
+	case UserCooldownScope:
+		return "user:" + ctx.User().ID
+	case MemberCooldownScope:
+		return "member:" + ctx.GuildId() + ":" + ctx.User().ID
+	case ChannelCooldownScope:
+		return "channel:" + ctx.ChannelId()
+	case GuildCooldownScope:
+		if ctx.GuildId() == "" {
+			return "channel:" + ctx.ChannelId()
+		}
+		return "guild:" + ctx.GuildId()
+	default:
+		return "global"
+	}
+}
+
+type CooldownStore interface {
+	Take(key string, cooldown Cooldown) (remaining time.Duration, ok bool)
+}
+
+func NewCooldownStore() CooldownStore {
+	return &MemoryCooldownStore{buckets: map[string]*cooldownBucket{}}
+}
+
+var defaultCooldownStore = NewCooldownStore()
+
+type cooldownBucket struct {
+	uses    []time.Time
+	expires time.Time
+}
+
+type MemoryCooldownStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*cooldownBucket
+	lastSweep time.Time
+}
+
+func (s *MemoryCooldownStore) Take(key string, cooldown Cooldown) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, b := range s.buckets {
+			if !now.Before(b.expires) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &cooldownBucket{}
+		s.buckets[key] = b
+	}
+
+	for len(b.uses) > 0 && !now.Before(b.uses[0].Add(cooldown.Duration)) {
+		b.uses = b.uses[1:]
+	}
+
+	if len(b.uses) >= max(cooldown.Burst, 1) {
+		return b.uses[0].Add(cooldown.Duration).Sub(now), false
+	}
+
+	b.uses = append(b.uses, now)
+	b.expires = now.Add(cooldown.Duration)
+	return 0, true
+}
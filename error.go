@@ -3,6 +3,8 @@ package commandhandler
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 var (
@@ -11,6 +13,13 @@ var (
 	InvalidSubCommandError  = errors.New("unknown subcommand")
 	RequiredOptionError     = errors.New("option required but not given")
 	InvalidOptionError      = errors.New("unknown option")
+	MissingPermissionsError = errors.New("you do not have permission to use this command")
+	NoReplyError            = errors.New("no reply has been sent")
+	AlreadyRespondedError   = errors.New("interaction has already been responded to")
+	UnterminatedQuoteError  = errors.New("unterminated quote")
+	UnterminatedCodeError   = errors.New("unterminated code block")
+	NotInGuildError         = errors.New("only available in guilds")
+	InternalError           = errors.New("something went wrong while running this command")
 )
 
 type OptionError struct {
@@ -23,6 +32,74 @@ type CommandError struct {
 	Err error
 }
 
+type HandlerError struct {
+	Hierarchy []string
+	Cmd       string
+	Opt       string
+	Options   []string
+	Values    map[string]any
+	Input     string
+	Err       error
+}
+
+func (e HandlerError) Error() string {
+	if e.Opt != "" {
+		return FormatOptionError(e.Hierarchy, e.Options, e.Values, e.Opt, e.Err)
+	}
+	return FormatCommandError(e.Hierarchy, e.Cmd, e.Err)
+}
+
+func (e HandlerError) Unwrap() error {
+	return e.Err
+}
+
+type CooldownError struct {
+	Remaining time.Duration
+}
+
+func (e CooldownError) Error() string {
+	return fmt.Sprintf("command is on cooldown, try again in %s", e.Remaining.Round(time.Second))
+}
+
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("command panicked: %v", e.Value)
+}
+
+type TokenizeError struct {
+	Pos int
+	Err error
+}
+
+func (e TokenizeError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Err, e.Pos)
+}
+
+func (e TokenizeError) Unwrap() error {
+	return e.Err
+}
+
+const maxAmbiguousCandidates = 10
+
+type AmbiguousArgumentError struct {
+	Arg        string
+	Candidates []string
+}
+
+func (e AmbiguousArgumentError) Error() string {
+	candidates := e.Candidates
+	more := ""
+	if len(candidates) > maxAmbiguousCandidates {
+		more = fmt.Sprintf(" and %d more", len(candidates)-maxAmbiguousCandidates)
+		candidates = candidates[:maxAmbiguousCandidates]
+	}
+	return fmt.Sprintf("'%s' is ambiguous, did you mean %s%s?", e.Arg, strings.Join(candidates, ", "), more)
+}
+
 func FormatOptionError(cmdHierarchy []string, opts []string, args map[string]any, opt string, err error) string {
 	message := "Command: "
 
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Aboshxm2/commandhandler"
+	"github.com/bwmarrin/discordgo"
+)
+
+var fruits = []string{"apple", "apricot", "banana", "blueberry", "cherry", "grape", "mango", "orange", "peach", "pear"}
+
+func initCommands(s *discordgo.Session) {
+	const prefix = "!"
+
+	cmds := []commandhandler.Command{
+		{
+			Name:        "fruit",
+			Description: "A command that suggests fruits while typing",
+			Options: []commandhandler.Option{
+				{
+					Name:        "name",
+					Description: "The fruit name",
+					Type:        commandhandler.StringOptionType,
+					Required:    true,
+					Autocomplete: func(ctx commandhandler.Context, partial any, opts map[string]any) []commandhandler.Choice {
+						choices := []commandhandler.Choice{}
+						for _, fruit := range fruits {
+							if strings.HasPrefix(fruit, strings.ToLower(partial.(string))) {
+								choices = append(choices, commandhandler.Choice{Name: fruit, Value: fruit})
+							}
+						}
+						return choices
+					},
+				},
+			},
+			Run: func(ctx commandhandler.Context, opts map[string]any) {
+				ctx.Reply("You picked " + opts["name"].(string))
+			},
+		},
+	}
+
+	resolver := commandhandler.NewResolver()
+	handler := commandhandler.NewHandler(prefix, cmds, resolver)
+
+	s.AddHandler(handler.OnMessageCreate)
+	s.AddHandler(handler.OnInteractionCreate)
+
+	builder := commandhandler.NewBuilder()
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
+	}
+}
+
+var (
+	guildId = flag.String("guild", "", "Register commands in specific guild. If not passed register globally")
+	token   = flag.String("token", "", "Bot token")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	dg, err := discordgo.New("Bot " + *token)
+	if err != nil {
+		fmt.Println("error creating Discord session,", err)
+		return
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuildMessages
+
+	err = dg.Open()
+	if err != nil {
+		fmt.Println("error opening connection,", err)
+		return
+	}
+
+	initCommands(dg)
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-sc
+
+	dg.Close()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Aboshxm2/commandhandler"
+	"github.com/bwmarrin/discordgo"
+)
+
+func initCommands(s *discordgo.Session) {
+	const prefix = "!"
+
+	cmds := []commandhandler.Command{
+		{
+			Name:        "vote",
+			Description: "A command that sends a message with vote buttons",
+			Options: []commandhandler.Option{
+				{
+					Name:        "topic",
+					Description: "The topic to vote on",
+					Type:        commandhandler.StringOptionType,
+					Required:    true,
+				},
+			},
+			Run: func(ctx commandhandler.Context, opts map[string]any) {
+				topic := opts["topic"].(string)
+				ctx.ReplyWith(commandhandler.ReplyOptions{
+					Content: "Vote: " + topic,
+					Components: []discordgo.MessageComponent{
+						discordgo.ActionsRow{
+							Components: []discordgo.MessageComponent{
+								discordgo.Button{Label: "Yes", Style: discordgo.SuccessButton, CustomID: "vote:" + topic + ":yes"},
+								discordgo.Button{Label: "No", Style: discordgo.DangerButton, CustomID: "vote:" + topic + ":no"},
+							},
+						},
+					},
+				})
+			},
+		},
+	}
+
+	components := commandhandler.NewComponentRouter()
+	components.Handle("vote:{topic}:{choice}", func(ctx *commandhandler.ComponentContext, params map[string]string) error {
+		return ctx.ReplyEphemeral(fmt.Sprintf("You voted %s on %s", params["choice"], params["topic"]))
+	})
+
+	handler := commandhandler.SimpleHandler{
+		Resolver:   commandhandler.NewResolver(),
+		Tokenizer:  commandhandler.NewTokenizer(),
+		Prefix:     prefix,
+		Commands:   cmds,
+		Components: components,
+	}
+
+	s.AddHandler(handler.OnMessageCreate)
+	s.AddHandler(handler.OnInteractionCreate)
+
+	builder := commandhandler.NewBuilder()
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
+	}
+}
+
+var (
+	guildId = flag.String("guild", "", "Register commands in specific guild. If not passed register globally")
+	token   = flag.String("token", "", "Bot token")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	dg, err := discordgo.New("Bot " + *token)
+	if err != nil {
+		fmt.Println("error creating Discord session,", err)
+		return
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuildMessages
+
+	err = dg.Open()
+	if err != nil {
+		fmt.Println("error opening connection,", err)
+		return
+	}
+
+	initCommands(dg)
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-sc
+
+	dg.Close()
+}
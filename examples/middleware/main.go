@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Aboshxm2/commandhandler"
+	"github.com/bwmarrin/discordgo"
+)
+
+func logger(next commandhandler.RunFunc) commandhandler.RunFunc {
+	return func(ctx commandhandler.Context, cmdHierarchy []string, opts map[string]any) error {
+		start := time.Now()
+		err := next(ctx, cmdHierarchy, opts)
+		fmt.Printf("%s %v took %s\n", strings.Join(cmdHierarchy, " "), opts, time.Since(start))
+		return err
+	}
+}
+
+func guildOnly(next commandhandler.RunFunc) commandhandler.RunFunc {
+	return func(ctx commandhandler.Context, cmdHierarchy []string, opts map[string]any) error {
+		if ctx.GuildId() == "" {
+			return errors.New("this command can only be used in a server")
+		}
+		return next(ctx, cmdHierarchy, opts)
+	}
+}
+
+func initCommands(s *discordgo.Session) {
+	const prefix = "!"
+
+	cmds := []commandhandler.Command{
+		{
+			Name:        "ping",
+			Description: "Ping!",
+			Run: func(ctx commandhandler.Context, opts map[string]any) {
+				ctx.Reply("Pong!")
+			},
+		},
+		{
+			Name:        "server",
+			Description: "A command that only works in servers",
+			Middlewares: []commandhandler.Middleware{guildOnly},
+			Run: func(ctx commandhandler.Context, opts map[string]any) {
+				ctx.Reply("This server's ID is " + ctx.GuildId())
+			},
+		},
+	}
+
+	handler := commandhandler.SimpleHandler{
+		Resolver:    commandhandler.NewResolver(),
+		Tokenizer:   commandhandler.NewTokenizer(),
+		Prefix:      prefix,
+		Commands:    cmds,
+		Middlewares: []commandhandler.Middleware{logger},
+	}
+
+	s.AddHandler(handler.OnMessageCreate)
+	s.AddHandler(handler.OnInteractionCreate)
+
+	builder := commandhandler.NewBuilder()
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
+	}
+}
+
+var (
+	guildId = flag.String("guild", "", "Register commands in specific guild. If not passed register globally")
+	token   = flag.String("token", "", "Bot token")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	dg, err := discordgo.New("Bot " + *token)
+	if err != nil {
+		fmt.Println("error creating Discord session,", err)
+		return
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuildMessages
+
+	err = dg.Open()
+	if err != nil {
+		fmt.Println("error opening connection,", err)
+		return
+	}
+
+	initCommands(dg)
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-sc
+
+	dg.Close()
+}
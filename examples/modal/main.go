@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Aboshxm2/commandhandler"
+	"github.com/bwmarrin/discordgo"
+)
+
+var feedbackModal = commandhandler.Modal{
+	CustomID: "feedback",
+	Title:    "Feedback",
+	Fields: []commandhandler.Option{
+		{
+			Name:        "rating",
+			Description: "Rating from 1 to 5",
+			Type:        commandhandler.IntegerOptionType,
+			Required:    true,
+			Rules: []commandhandler.Rule{
+				commandhandler.MinInt{Min: 1},
+				commandhandler.MaxInt{Max: 5},
+				commandhandler.MaxString{Max: 1},
+			},
+		},
+		{
+			Name:        "comment",
+			Description: "Anything else?",
+			Type:        commandhandler.StringOptionType,
+			Rest:        true,
+			Rules: []commandhandler.Rule{
+				commandhandler.MaxString{Max: 1000},
+			},
+		},
+	},
+	Submit: func(ctx *commandhandler.ModalContext, fields map[string]any) error {
+		return ctx.ReplyEphemeral(fmt.Sprintf("Thanks for rating us %d/5!", fields["rating"].(int64)))
+	},
+}
+
+func initCommands(s *discordgo.Session) {
+	const prefix = "!"
+
+	cmds := []commandhandler.Command{
+		{
+			Name:        "feedback",
+			Description: "A command that opens a feedback form",
+			RunE: func(ctx commandhandler.Context, opts map[string]any) error {
+				slash, ok := ctx.(*commandhandler.SlashCommandContext)
+				if !ok {
+					return ctx.Reply("Please use /feedback instead")
+				}
+				return slash.ShowModal(feedbackModal)
+			},
+		},
+	}
+
+	handler := commandhandler.SimpleHandler{
+		Resolver:  commandhandler.NewResolver(),
+		Tokenizer: commandhandler.NewTokenizer(),
+		Prefix:    prefix,
+		Commands:  cmds,
+		Modals:    []commandhandler.Modal{feedbackModal},
+	}
+
+	s.AddHandler(handler.OnMessageCreate)
+	s.AddHandler(handler.OnInteractionCreate)
+
+	builder := commandhandler.NewBuilder()
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
+	}
+}
+
+var (
+	guildId = flag.String("guild", "", "Register commands in specific guild. If not passed register globally")
+	token   = flag.String("token", "", "Bot token")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	dg, err := discordgo.New("Bot " + *token)
+	if err != nil {
+		fmt.Println("error creating Discord session,", err)
+		return
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuildMessages
+
+	err = dg.Open()
+	if err != nil {
+		fmt.Println("error opening connection,", err)
+		return
+	}
+
+	initCommands(dg)
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-sc
+
+	dg.Close()
+}
@@ -35,11 +35,9 @@ func initCommands(s *discordgo.Session) {
 	s.AddHandler(handler.OnInteractionCreate)
 
 	builder := commandhandler.NewBuilder()
-	for _, cmd := range cmds {
-		_, err := s.ApplicationCommandCreate(s.State.Application.ID, *guildId, builder.Build(cmd))
-		if err != nil {
-			fmt.Println("error creating discord command,", err)
-		}
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
 	}
 }
 
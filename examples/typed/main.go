@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Aboshxm2/commandhandler"
+	"github.com/bwmarrin/discordgo"
+)
+
+type RepeatArgs struct {
+	Text  string `option:",required" description:"The text to repeat" max:"100"`
+	Times int    `description:"How many times to repeat the text" min:"1" max:"5"`
+}
+
+func initCommands(s *discordgo.Session) {
+	const prefix = "!"
+
+	cmds := []commandhandler.Command{
+		commandhandler.Typed(commandhandler.Command{
+			Name:        "repeat",
+			Description: "A command with options derived from a struct",
+		}, func(ctx commandhandler.Context, args RepeatArgs) {
+			message := ""
+			for i := 0; i < max(args.Times, 1); i++ {
+				message += args.Text + "\n"
+			}
+			ctx.Reply(message)
+		}),
+	}
+
+	resolver := commandhandler.NewResolver()
+	handler := commandhandler.NewHandler(prefix, cmds, resolver)
+
+	s.AddHandler(handler.OnMessageCreate)
+	s.AddHandler(handler.OnInteractionCreate)
+
+	builder := commandhandler.NewBuilder()
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
+	}
+}
+
+var (
+	guildId = flag.String("guild", "", "Register commands in specific guild. If not passed register globally")
+	token   = flag.String("token", "", "Bot token")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	dg, err := discordgo.New("Bot " + *token)
+	if err != nil {
+		fmt.Println("error creating Discord session,", err)
+		return
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuildMessages
+
+	err = dg.Open()
+	if err != nil {
+		fmt.Println("error opening connection,", err)
+		return
+	}
+
+	initCommands(dg)
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-sc
+
+	dg.Close()
+}
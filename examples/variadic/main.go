@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Aboshxm2/commandhandler"
+	"github.com/bwmarrin/discordgo"
+)
+
+func initCommands(s *discordgo.Session) {
+	const prefix = "!"
+
+	cmds := []commandhandler.Command{
+		{
+			Name:        "greet",
+			Description: "A command that greets one or more users",
+			Options: []commandhandler.Option{
+				{
+					Name:        "user",
+					Description: "A user to greet",
+					Type:        commandhandler.UserOptionType,
+					Required:    true,
+					Variadic:    true,
+					Rules: []commandhandler.Rule{
+						commandhandler.MaxItems{Max: 5},
+					},
+				},
+			},
+			Run: func(ctx commandhandler.Context, opts map[string]any) {
+				names := []string{}
+				for _, user := range opts["user"].([]*discordgo.User) {
+					names = append(names, user.Mention())
+				}
+				ctx.Reply("Hello " + strings.Join(names, ", "))
+			},
+		},
+	}
+
+	resolver := commandhandler.NewResolver()
+	handler := commandhandler.NewHandler(prefix, cmds, resolver)
+
+	s.AddHandler(handler.OnMessageCreate)
+	s.AddHandler(handler.OnInteractionCreate)
+
+	builder := commandhandler.NewBuilder()
+	_, err := commandhandler.Sync(s, s.State.Application.ID, *guildId, cmds, builder, commandhandler.SyncOptions{})
+	if err != nil {
+		fmt.Println("error syncing discord commands,", err)
+	}
+}
+
+var (
+	guildId = flag.String("guild", "", "Register commands in specific guild. If not passed register globally")
+	token   = flag.String("token", "", "Bot token")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	dg, err := discordgo.New("Bot " + *token)
+	if err != nil {
+		fmt.Println("error creating Discord session,", err)
+		return
+	}
+
+	dg.Identify.Intents = discordgo.IntentsGuildMessages
+
+	err = dg.Open()
+	if err != nil {
+		fmt.Println("error opening connection,", err)
+		return
+	}
+
+	initCommands(dg)
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-sc
+
+	dg.Close()
+}
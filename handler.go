@@ -1,8 +1,12 @@
 package commandhandler
 
 import (
-	"regexp"
+	"errors"
+	"log"
+	"maps"
+	"runtime/debug"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -15,104 +19,410 @@ type Handler interface {
 
 func NewHandler(prefix string, cmds []Command, resolver Resolver) Handler {
 	return SimpleHandler{
-		resolver, prefix, cmds,
+		Resolver:       resolver,
+		Tokenizer:      NewTokenizer(),
+		Prefix:         prefix,
+		Commands:       cmds,
+		Cooldowns:      NewCooldownStore(),
+		ErrorPresenter: TextErrorPresenter{},
 	}
 }
 
 type SimpleHandler struct {
-	resolver Resolver
-	prefix   string
-	cmds     []Command
+	Resolver              Resolver
+	Tokenizer             Tokenizer
+	Prefix                string
+	Commands              []Command
+	Middlewares           []Middleware
+	Cooldowns             CooldownStore
+	OnError               func(ctx Context, cmdHierarchy []string, err error)
+	ErrorPresenter        ErrorPresenter
+	IgnoreUnknownCommands bool
+	Components            *ComponentRouter
+	Modals                []Modal
 }
 
 func (h SimpleHandler) OnMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
-	if !strings.HasPrefix(m.Content, h.prefix) {
+	if !strings.HasPrefix(m.Content, h.Prefix) {
 		return
 	}
 
-	args := getArgs(m.Content, h.prefix)
+	ctx := MessageToContext(s, m.Message)
 
-	cmd, cmdHierarchy, args, cmdErr := parseArgs(h.cmds, args)
+	var cmdHierarchy []string
+	defer func() {
+		if r := recover(); r != nil {
+			h.onError(ctx, cmdHierarchy, PanicError{r, debug.Stack()})
+		}
+	}()
 
-	ctx := MessageToContext(s, m.Message)
+	tokenizer := h.Tokenizer
+	if tokenizer == nil {
+		tokenizer = NewTokenizer()
+	}
+
+	tokens, err := tokenizeLenient(tokenizer, strings.TrimPrefix(m.Content, h.Prefix))
+	if err != nil {
+		h.presentError(ctx, HandlerError{Input: m.Content, Err: err})
+		return
+	}
+
+	cmd, cmdHierarchy, args, cmdErr := parseArgs(h.Commands, tokens)
 
 	if cmdErr.Err != nil {
-		ctx.Reply(FormatCommandError(cmdHierarchy, cmdErr.Cmd, cmdErr.Err))
+		if h.IgnoreUnknownCommands && errors.Is(cmdErr.Err, CommandNotFoundError) {
+			return
+		}
+		h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Cmd: cmdErr.Cmd, Input: m.Content, Err: cmdErr.Err})
 		return
 	}
 
-	opts, optErr := h.resolver.ResolveMessageOptions(cmd, ctx, args)
+	chain := commandChain(h.Commands, cmdHierarchy)
 
-	if optErr.Err != nil {
-		args := []string{}
-		for _, opt := range cmd.Options {
-			args = append(args, opt.Name)
+	if perms := commandPermissions(chain); perms != 0 && m.GuildID != "" {
+		p, err := s.State.MessagePermissions(m.Message)
+		if err != nil {
+			p, err = s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+		}
+		if err != nil || p&perms != perms {
+			h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Cmd: cmdHierarchy[len(cmdHierarchy)-1], Input: m.Content, Err: MissingPermissionsError})
+			return
 		}
-		ctx.Reply(FormatOptionError(cmdHierarchy, args, opts, optErr.Opt, optErr.Err))
+	}
+
+	if err := h.takeCooldown(ctx, chain); err != nil {
+		h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Cmd: cmdHierarchy[len(cmdHierarchy)-1], Input: m.Content, Err: err})
+		return
+	}
+
+	opts, optErr := h.Resolver.ResolveMessageOptions(cmd, ctx, args)
+
+	if optErr.Err != nil {
+		h.presentError(ctx, optionError(cmd, cmdHierarchy, opts, optErr, m.Content))
 		return
 	}
 
 	optErr = Validate(cmd.Options, opts)
 
 	if optErr.Err != nil {
-		args := []string{}
-		for _, opt := range cmd.Options {
-			args = append(args, opt.Name)
-		}
-		ctx.Reply(FormatOptionError(cmdHierarchy, args, opts, optErr.Opt, optErr.Err))
+		h.presentError(ctx, optionError(cmd, cmdHierarchy, opts, optErr, m.Content))
 		return
 	}
 
-	cmd.Run(ctx, opts)
+	h.run(ctx, chain, cmdHierarchy, opts)
 }
 
 func (h SimpleHandler) OnInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		h.onApplicationCommand(s, i)
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		h.onAutocomplete(s, i)
+	case discordgo.InteractionMessageComponent:
+		h.onComponent(s, i)
+	case discordgo.InteractionModalSubmit:
+		h.onModalSubmit(s, i)
+	}
+}
+
+func (h SimpleHandler) onApplicationCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	ctx := SlashCommandToContext(s, i)
 
-	cmd, cmdHierarchy, cmdErr := parseSlashCommandArgs(h.cmds, i)
+	var cmdHierarchy []string
+	defer func() {
+		if r := recover(); r != nil {
+			h.onError(ctx, cmdHierarchy, PanicError{r, debug.Stack()})
+		}
+	}()
+
+	cmd, cmdHierarchy, args, cmdErr := parseSlashCommandArgs(h.Commands, i)
 	if cmdErr.Err != nil {
-		ctx.Reply(FormatCommandError(cmdHierarchy, cmdErr.Cmd, cmdErr.Err))
+		h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Cmd: cmdErr.Cmd, Err: cmdErr.Err})
+		return
+	}
+
+	chain := []Command{cmd}
+	if cmd.Kind == ChatInputCommandKind {
+		chain = commandChain(h.Commands, cmdHierarchy)
+	}
+
+	if perms := commandPermissions(chain[1:]); perms != 0 && i.Member != nil {
+		if i.Member.Permissions&perms != perms {
+			h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Cmd: cmdHierarchy[len(cmdHierarchy)-1], Err: MissingPermissionsError})
+			return
+		}
+	}
+
+	if err := h.takeCooldown(ctx, chain); err != nil {
+		h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Cmd: cmdHierarchy[len(cmdHierarchy)-1], Err: err})
 		return
 	}
 
 	var opts map[string]any
 	var optErr OptionError
 
-	switch len(cmdHierarchy) {
-	case 1:
-		opts, optErr = h.resolver.ResolveSlashCommandOptions(cmd, ctx, i.ApplicationCommandData().Options)
-	case 2:
-		opts, optErr = h.resolver.ResolveSlashCommandOptions(cmd, ctx, i.ApplicationCommandData().Options[0].Options)
-	case 3:
-		opts, optErr = h.resolver.ResolveSlashCommandOptions(cmd, ctx, i.ApplicationCommandData().Options[0].Options[0].Options)
+	if cmd.Kind == ChatInputCommandKind {
+		opts, optErr = h.Resolver.ResolveSlashCommandOptions(cmd, ctx, args)
+	} else {
+		opts = contextMenuTarget(i.ApplicationCommandData())
 	}
 
 	if optErr.Err != nil {
-		args := []string{}
-		for _, opt := range cmd.Options {
-			args = append(args, opt.Name)
-		}
-		ctx.Reply(FormatOptionError(cmdHierarchy, args, opts, optErr.Opt, optErr.Err))
+		h.presentError(ctx, optionError(cmd, cmdHierarchy, opts, optErr, ""))
 		return
 	}
 
 	optErr = Validate(cmd.Options, opts)
 
 	if optErr.Err != nil {
-		args := []string{}
-		for _, opt := range cmd.Options {
-			args = append(args, opt.Name)
+		h.presentError(ctx, optionError(cmd, cmdHierarchy, opts, optErr, ""))
+		return
+	}
+
+	h.run(ctx, chain, cmdHierarchy, opts)
+}
+
+func (h SimpleHandler) onAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx := SlashCommandToContext(s, i)
+
+	var cmdHierarchy []string
+	defer func() {
+		if r := recover(); r != nil {
+			// An autocomplete interaction cannot carry a message, so answer it
+			// with no choices and only report the panic.
+			var err error = PanicError{r, debug.Stack()}
+			if respondErr := respondAutocomplete(s, i, nil); respondErr != nil {
+				err = errors.Join(err, respondErr)
+			}
+			h.onAutocompleteError(ctx, cmdHierarchy, err)
+		}
+	}()
+
+	cmd, cmdHierarchy, args, cmdErr := parseSlashCommandArgs(h.Commands, i)
+	if cmdErr.Err != nil {
+		return
+	}
+
+	var focused *discordgo.ApplicationCommandInteractionDataOption
+	others := []*discordgo.ApplicationCommandInteractionDataOption{}
+	for _, arg := range args {
+		if arg.Focused {
+			focused = arg
+		} else {
+			others = append(others, arg)
+		}
+	}
+
+	if focused == nil {
+		return
+	}
+
+	opt, ok := findSlashCommandOption(cmd.Options, focused.Name)
+	if !ok || opt.Autocomplete == nil {
+		return
+	}
+
+	opts, optErr := h.Resolver.ResolveSlashCommandOptions(cmd, ctx, others)
+	if optErr.Err != nil {
+		delete(opts, optErr.Opt)
+	}
+
+	if err := respondAutocomplete(s, i, opt.Autocomplete(ctx, focused.Value, opts)); err != nil {
+		h.onAutocompleteError(ctx, cmdHierarchy, err)
+	}
+}
+
+func respondAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, choices []Choice) error {
+	data := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, c := range choices {
+		if len(data) == 25 {
+			break
+		}
+		data = append(data, &discordgo.ApplicationCommandOptionChoice{
+			Name:  c.Name,
+			Value: c.Value,
+		})
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: data,
+		},
+	})
+}
+
+func (h SimpleHandler) onAutocompleteError(ctx Context, cmdHierarchy []string, err error) {
+	if h.OnError != nil {
+		h.OnError(ctx, cmdHierarchy, err)
+		return
+	}
+
+	var panicErr PanicError
+	if errors.As(err, &panicErr) {
+		log.Printf("commandhandler: panic in autocomplete for %q: %v\n%s", strings.Join(cmdHierarchy, " "), panicErr.Value, panicErr.Stack)
+		return
+	}
+	log.Printf("commandhandler: autocomplete for %q failed: %v", strings.Join(cmdHierarchy, " "), err)
+}
+
+func (h SimpleHandler) onComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h.Components == nil {
+		return
+	}
+
+	ctx := ComponentToContext(s, i)
+
+	defer func() {
+		if r := recover(); r != nil {
+			h.onError(ctx, nil, PanicError{r, debug.Stack()})
 		}
-		ctx.Reply(FormatOptionError(cmdHierarchy, args, opts, optErr.Opt, optErr.Err))
+	}()
+
+	messageID := ""
+	if i.Message != nil {
+		messageID = i.Message.ID
+	}
+
+	handler, params, ok := h.Components.match(messageID, i.MessageComponentData().CustomID)
+	if !ok {
 		return
 	}
 
-	cmd.Run(ctx, opts)
+	if err := handler(ctx, params); err != nil {
+		h.onError(ctx, nil, err)
+	}
+}
+
+func (h SimpleHandler) onModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx := ModalToContext(s, i)
+
+	defer func() {
+		if r := recover(); r != nil {
+			h.onError(ctx, nil, PanicError{r, debug.Stack()})
+		}
+	}()
+
+	idx := slices.IndexFunc(h.Modals, func(m Modal) bool { return m.CustomID == ctx.CustomID() })
+	if idx < 0 {
+		return
+	}
+	modal := h.Modals[idx]
+
+	resolver, ok := h.Resolver.(ModalResolver)
+	if !ok {
+		resolver = NewResolver().(ModalResolver)
+	}
+
+	fields, optErr := resolver.ResolveModalOptions(modal.Fields, ctx, ctx.Values())
+	if optErr.Err == nil {
+		optErr = Validate(modal.Fields, fields)
+	}
+
+	if optErr.Err != nil {
+		h.presentError(ctx, optionError(Command{Options: modal.Fields}, nil, fields, optErr, ""))
+		return
+	}
+
+	if err := modal.Submit(ctx, fields); err != nil {
+		h.onError(ctx, nil, err)
+	}
+}
+
+func (h SimpleHandler) takeCooldown(ctx Context, chain []Command) error {
+	cmd := chain[len(chain)-1]
+	if cmd.Cooldown.Duration <= 0 {
+		return nil
+	}
+
+	store := h.Cooldowns
+	if store == nil {
+		store = defaultCooldownStore
+	}
+
+	names := []string{}
+	for _, c := range chain {
+		names = append(names, c.Name)
+	}
+
+	if remaining, ok := store.Take(strings.Join(names, " ")+":"+cmd.Cooldown.key(ctx), cmd.Cooldown); !ok {
+		return CooldownError{remaining}
+	}
+	return nil
+}
+
+func (h SimpleHandler) run(ctx Context, chain []Command, cmdHierarchy []string, opts map[string]any) {
+	middlewares := slices.Clone(h.Middlewares)
+	for _, cmd := range chain {
+		middlewares = append(middlewares, cmd.Middlewares...)
+	}
+
+	cmd := chain[len(chain)-1]
+	next := RunFunc(func(ctx Context, cmdHierarchy []string, opts map[string]any) error {
+		if cmd.RunE != nil {
+			return cmd.RunE(ctx, opts)
+		}
+		cmd.Run(ctx, opts)
+		return nil
+	})
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+
+	if err := next(ctx, cmdHierarchy, opts); err != nil {
+		h.onError(ctx, cmdHierarchy, err)
+	}
+}
+
+func (h SimpleHandler) onError(ctx Context, cmdHierarchy []string, err error) {
+	if h.OnError != nil {
+		h.OnError(ctx, cmdHierarchy, err)
+		return
+	}
+
+	var panicErr PanicError
+	if errors.As(err, &panicErr) {
+		log.Printf("commandhandler: panic in command %q: %v\n%s", strings.Join(cmdHierarchy, " "), panicErr.Value, panicErr.Stack)
+		err = InternalError
+	}
+	h.presentError(ctx, HandlerError{Hierarchy: cmdHierarchy, Err: err})
+}
+
+func (h SimpleHandler) presentError(ctx Context, err HandlerError) {
+	presenter := h.ErrorPresenter
+	if presenter == nil {
+		presenter = TextErrorPresenter{}
+	}
+	presenter.Present(ctx, err)
+}
+
+func optionError(cmd Command, cmdHierarchy []string, values map[string]any, optErr OptionError, input string) HandlerError {
+	opts := []string{}
+	for _, opt := range cmd.Options {
+		opts = append(opts, opt.Name)
+	}
+
+	return HandlerError{
+		Hierarchy: cmdHierarchy,
+		Opt:       optErr.Opt,
+		Options:   opts,
+		Values:    values,
+		Input:     input,
+		Err:       optErr.Err,
+	}
 }
 
 func findCommand(cmds []Command, name string) (Command, bool) {
+	return findCommandOfKind(cmds, name, ChatInputCommandKind)
+}
+
+func findCommandOfKind(cmds []Command, name string, kind CommandKind) (Command, bool) {
 	for _, cmd := range cmds {
-		if cmd.Name == name || slices.Contains(cmd.Aliases, name) {
+		if cmd.Kind != kind {
+			continue
+		}
+		if cmd.Name == name || slices.Contains(cmd.Aliases, name) || slices.Contains(slices.Collect(maps.Values(cmd.NameLocalizations)), name) {
 			return cmd, true
 		}
 	}
@@ -120,25 +430,64 @@ func findCommand(cmds []Command, name string) (Command, bool) {
 	return Command{}, false
 }
 
-func getArgs(message string, prefix string) []string {
-	args := []string{}
+func commandChain(cmds []Command, cmdHierarchy []string) (chain []Command) {
+	for _, name := range cmdHierarchy {
+		cmd, ok := findCommand(cmds, name)
+		if !ok {
+			break
+		}
+		chain = append(chain, cmd)
+		cmds = cmd.Subs
+	}
 
-	regex := regexp.MustCompile(`"(.*)"|([^"\s]*)`)
-	for _, match := range regex.FindAllStringSubmatch(strings.TrimPrefix(message, prefix), -1) {
-		if match[1] != "" {
-			args = append(args, match[1])
-		} else {
-			args = append(args, match[2])
+	return
+}
+
+func commandPermissions(chain []Command) (perms int64) {
+	for _, cmd := range chain {
+		perms |= cmd.Permissions
+	}
+
+	return
+}
+
+func findOption(opts []Option, name string) (Option, bool) {
+	for _, opt := range opts {
+		if opt.Name == name {
+			return opt, true
 		}
 	}
 
-	return args
+	return Option{}, false
 }
 
-func parseArgs(cmds []Command, args []string) (lastCmd Command, cmdHierarchy []string, newArgs []string, err CommandError) {
-	for _, arg := range args {
+// findSlashCommandOption is like findOption but also maps the numbered options
+// of a variadic option (name1, name2, ...) back to it.
+func findSlashCommandOption(opts []Option, name string) (Option, bool) {
+	if opt, ok := findOption(opts, name); ok {
+		return opt, true
+	}
+
+	for _, opt := range opts {
+		if suffix, ok := strings.CutPrefix(name, opt.Name); ok && opt.Variadic {
+			if n, err := strconv.Atoi(suffix); err == nil && n > 0 {
+				return opt, true
+			}
+		}
+	}
+
+	return Option{}, false
+}
+
+func parseArgs(cmds []Command, args []Token) (lastCmd Command, cmdHierarchy []string, newArgs []Token, err CommandError) {
+	for _, token := range args {
+		arg := token.Value
 		cmd, ok := findCommand(cmds, arg)
 		if !ok {
+			if len(cmdHierarchy) == 0 {
+				err = CommandError{arg, CommandNotFoundError}
+				return
+			}
 			if len(lastCmd.Subs) > 0 {
 				err = CommandError{arg, InvalidSubCommandError}
 				return
@@ -155,6 +504,11 @@ func parseArgs(cmds []Command, args []string) (lastCmd Command, cmdHierarchy []s
 		}
 	}
 
+	if len(cmdHierarchy) == 0 {
+		err = CommandError{"", CommandNotFoundError}
+		return
+	}
+
 	if len(lastCmd.Subs) > 0 {
 		err = CommandError{"", RequiredSubCommandError}
 		return
@@ -165,37 +519,60 @@ func parseArgs(cmds []Command, args []string) (lastCmd Command, cmdHierarchy []s
 	return
 }
 
-func parseSlashCommandArgs(cmds []Command, i *discordgo.InteractionCreate) (cmd Command, cmdHierarchy []string, err CommandError) {
+func parseSlashCommandArgs(cmds []Command, i *discordgo.InteractionCreate) (cmd Command, cmdHierarchy []string, args []*discordgo.ApplicationCommandInteractionDataOption, err CommandError) {
 	d := i.ApplicationCommandData()
 
-	if len(d.Options) == 0 || (d.Options[0].Type != discordgo.ApplicationCommandOptionSubCommandGroup && d.Options[0].Type != discordgo.ApplicationCommandOptionSubCommand) {
-		c, ok := findCommand(cmds, d.Name)
+	kind := ChatInputCommandKind
+	switch d.CommandType {
+	case discordgo.UserApplicationCommand:
+		kind = UserCommandKind
+	case discordgo.MessageApplicationCommand:
+		kind = MessageCommandKind
+	}
+
+	cmd, ok := findCommandOfKind(cmds, d.Name, kind)
+	if !ok {
+		err = CommandError{d.Name, CommandNotFoundError}
+		return
+	}
+	cmdHierarchy = append(cmdHierarchy, cmd.Name)
+	args = d.Options
+
+	for len(args) > 0 && (args[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup || args[0].Type == discordgo.ApplicationCommandOptionSubCommand) {
+		sub, ok := findCommand(cmd.Subs, args[0].Name)
 		if !ok {
-			err.Err = CommandNotFoundError
+			err = CommandError{args[0].Name, InvalidSubCommandError}
 			return
 		}
-		cmdHierarchy = append(cmdHierarchy, c.Name)
-		cmd = c
-	} else if d.Options[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup {
-		c1, ok1 := findCommand(cmds, d.Name)
-		c2, ok2 := findCommand(c1.Subs, d.Options[0].Name)
-		c3, ok3 := findCommand(c2.Subs, d.Options[0].Options[0].Name)
-		if !ok1 || !ok2 || !ok3 {
-			err.Err = CommandNotFoundError
-			return
-		}
-		cmdHierarchy = append(cmdHierarchy, c1.Name, c2.Name, c3.Name)
-		cmd = c3
-	} else {
-		c1, ok1 := findCommand(cmds, d.Name)
-		c2, ok2 := findCommand(c1.Subs, d.Options[0].Name)
-		if !ok1 || !ok2 {
-			err.Err = CommandNotFoundError
-			return
-		}
-		cmdHierarchy = append(cmdHierarchy, c1.Name, c2.Name)
-		cmd = c2
+		cmd = sub
+		cmdHierarchy = append(cmdHierarchy, sub.Name)
+		args = args[0].Options
+	}
+
+	if len(cmd.Subs) > 0 {
+		err = CommandError{"", RequiredSubCommandError}
 	}
 
 	return
 }
+
+func contextMenuTarget(d discordgo.ApplicationCommandInteractionData) map[string]any {
+	opts := map[string]any{}
+	if d.Resolved == nil {
+		return opts
+	}
+
+	if user, ok := d.Resolved.Users[d.TargetID]; ok {
+		opts[TargetUserOption] = user
+		if member, ok := d.Resolved.Members[d.TargetID]; ok {
+			member.User = user
+			opts[TargetMemberOption] = member
+		}
+	}
+
+	if message, ok := d.Resolved.Messages[d.TargetID]; ok {
+		opts[TargetMessageOption] = message
+	}
+
+	return opts
+}
@@ -0,0 +1,5 @@
+package commandhandler
+
+type RunFunc func(ctx Context, cmdHierarchy []string, opts map[string]any) error
+
+type Middleware func(next RunFunc) RunFunc
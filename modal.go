@@ -0,0 +1,156 @@
+package commandhandler
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type Modal struct {
+	CustomID string
+	Title    string
+	// Fields are rendered as text inputs labelled with their description. Rest
+	// fields use the paragraph style.
+	Fields []Option
+	Submit func(ctx *ModalContext, fields map[string]any) error
+}
+
+// Validate reports every field that Discord would reject when the modal is
+// shown, with paths like "feedback.comment".
+func (m Modal) Validate() []error {
+	errs := []error{}
+	report := func(path string, format string, args ...any) {
+		errs = append(errs, ValidationError{path, fmt.Errorf(format, args...)})
+	}
+
+	if l := utf8.RuneCountInString(m.CustomID); l < 1 || l > 100 {
+		report(m.CustomID, "custom id must be between 1 and 100 characters long")
+	}
+	if l := utf8.RuneCountInString(m.Title); l < 1 || l > 45 {
+		report(m.CustomID, "title must be between 1 and 45 characters long")
+	}
+	if l := len(m.Fields); l < 1 || l > 5 {
+		report(m.CustomID, "a modal must have between 1 and 5 fields")
+	}
+
+	for _, field := range m.Fields {
+		path := m.CustomID + "." + field.Name
+
+		if l := utf8.RuneCountInString(field.Name); l < 1 || l > 100 {
+			report(path, "name must be between 1 and 100 characters long")
+		}
+		if l := utf8.RuneCountInString(field.Description); l < 1 || l > 45 {
+			report(path, "description is used as the label and must be between 1 and 45 characters long")
+		}
+
+		for _, rule := range field.Rules {
+			switch r := rule.(type) {
+			case MinString:
+				if r.Min < 0 || r.Min > 4000 {
+					report(path, "minimum length must be between 0 and 4000")
+				}
+			case MaxString:
+				if r.Max < 1 || r.Max > 4000 {
+					report(path, "maximum length must be between 1 and 4000")
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+func (m Modal) build() (*discordgo.InteractionResponseData, error) {
+	if errs := m.Validate(); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	rows := []discordgo.MessageComponent{}
+
+	for _, field := range m.Fields {
+		input := discordgo.TextInput{
+			CustomID: field.Name,
+			Label:    field.Description,
+			Style:    discordgo.TextInputShort,
+			Required: field.Required,
+		}
+
+		if field.Rest {
+			input.Style = discordgo.TextInputParagraph
+		}
+
+		for _, rule := range field.Rules {
+			switch r := rule.(type) {
+			case MinString:
+				input.MinLength = r.Min
+			case MaxString:
+				input.MaxLength = r.Max
+			}
+		}
+
+		rows = append(rows, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{input},
+		})
+	}
+
+	return &discordgo.InteractionResponseData{
+		CustomID:   m.CustomID,
+		Title:      m.Title,
+		Components: rows,
+	}, nil
+}
+
+func (ctx SlashCommandContext) ShowModal(m Modal) error {
+	ctx.state.mu.Lock()
+	defer ctx.state.mu.Unlock()
+
+	if ctx.state.status != notResponded {
+		return AlreadyRespondedError
+	}
+
+	data, err := m.build()
+	if err != nil {
+		return err
+	}
+
+	err = ctx.s.InteractionRespond(ctx.i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx.state.status = responded
+	return nil
+}
+
+type ModalContext struct {
+	SlashCommandContext
+}
+
+func (ctx ModalContext) CustomID() string { return ctx.i.ModalSubmitData().CustomID }
+
+func (ctx ModalContext) Values() map[string]string {
+	values := map[string]string{}
+
+	for _, row := range ctx.i.ModalSubmitData().Components {
+		r, ok := row.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, c := range r.Components {
+			if input, ok := c.(*discordgo.TextInput); ok {
+				values[input.CustomID] = input.Value
+			}
+		}
+	}
+
+	return values
+}
+
+func ModalToContext(s *discordgo.Session, i *discordgo.InteractionCreate) *ModalContext {
+	return &ModalContext{SlashCommandContext{s, i.Interaction, &responseState{}}}
+}
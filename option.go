@@ -1,28 +1,46 @@
 package commandhandler
 
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
 type OptionType uint8
 
 const (
-	StringOptionType  OptionType = 0
-	IntegerOptionType OptionType = 1
-	FloatOptionType   OptionType = 2
-	BooleanOptionType OptionType = 3
-	UserOptionType    OptionType = 4
-	MemberOptionType  OptionType = 5
-	ChannelOptionType OptionType = 6
-	RoleOptionType    OptionType = 7
+	StringOptionType      OptionType = 0
+	IntegerOptionType     OptionType = 1
+	FloatOptionType       OptionType = 2
+	BooleanOptionType     OptionType = 3
+	UserOptionType        OptionType = 4
+	MemberOptionType      OptionType = 5
+	ChannelOptionType     OptionType = 6
+	RoleOptionType        OptionType = 7
+	AttachmentOptionType  OptionType = 8
+	MentionableOptionType OptionType = 9
 )
 
 type Choice struct {
-	Name  string
-	Value any
+	Name              string
+	Value             any
+	NameLocalizations map[discordgo.Locale]string
 }
 
 type Option struct {
 	Name        string
 	Type        OptionType
 	Description string
-	Required    bool
-	Choices     []Choice
-	Rules       []Rule
+
+	NameLocalizations        map[discordgo.Locale]string
+	DescriptionLocalizations map[discordgo.Locale]string
+
+	Required bool
+	Default  any
+	Choices  []Choice
+	Rules    []Rule
+	Rest     bool
+	// Variadic options collect every remaining value into a typed slice. Slash
+	// commands expose them as numbered options (name1, name2, ...) bounded by
+	// the MinItems and MaxItems rules, which are merged back by the resolver.
+	Variadic     bool
+	Autocomplete func(ctx Context, partial any, opts map[string]any) []Choice
 }
@@ -0,0 +1,52 @@
+package commandhandler
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+type Options map[string]any
+
+func Get[T any](opts Options, name string) (T, bool) {
+	v, ok := opts[name].(T)
+	return v, ok
+}
+
+func GetOr[T any](opts Options, name string, def T) T {
+	if v, ok := Get[T](opts, name); ok {
+		return v
+	}
+	return def
+}
+
+func (opts Options) Has(name string) bool {
+	_, ok := opts[name]
+	return ok
+}
+
+func (opts Options) String(name string) string { return GetOr(opts, name, "") }
+
+func (opts Options) Int(name string) int64 { return GetOr[int64](opts, name, 0) }
+
+func (opts Options) Float(name string) float64 { return GetOr[float64](opts, name, 0) }
+
+func (opts Options) Bool(name string) bool { return GetOr(opts, name, false) }
+
+func (opts Options) User(name string) *discordgo.User {
+	return GetOr[*discordgo.User](opts, name, nil)
+}
+
+func (opts Options) Member(name string) *discordgo.Member {
+	return GetOr[*discordgo.Member](opts, name, nil)
+}
+
+func (opts Options) Channel(name string) *discordgo.Channel {
+	return GetOr[*discordgo.Channel](opts, name, nil)
+}
+
+func (opts Options) Role(name string) *discordgo.Role {
+	return GetOr[*discordgo.Role](opts, name, nil)
+}
+
+func (opts Options) Attachment(name string) *discordgo.MessageAttachment {
+	return GetOr[*discordgo.MessageAttachment](opts, name, nil)
+}
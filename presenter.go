@@ -0,0 +1,65 @@
+package commandhandler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type ErrorPresenter interface {
+	Present(ctx Context, err HandlerError) error
+}
+
+type TextErrorPresenter struct {
+	Catalog MessageCatalog
+}
+
+func (p TextErrorPresenter) Present(ctx Context, err HandlerError) error {
+	return ctx.Reply(LocalizeError(ctx, p.Catalog, err).Error())
+}
+
+type EmbedErrorPresenter struct {
+	Title     string
+	Color     int
+	Ephemeral bool
+	Catalog   MessageCatalog
+}
+
+func (p EmbedErrorPresenter) embed(err HandlerError) *discordgo.MessageEmbed {
+	title := p.Title
+	if title == "" {
+		title = "Error"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: err.Err.Error(),
+		Color:       p.Color,
+	}
+
+	if len(err.Hierarchy) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Command",
+			Value:  strings.Join(err.Hierarchy, " "),
+			Inline: true,
+		})
+	}
+
+	if err.Opt != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Option",
+			Value:  fmt.Sprintf("%s:%v", err.Opt, err.Values[err.Opt]),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+func (p EmbedErrorPresenter) Present(ctx Context, err HandlerError) error {
+	return ctx.ReplyWith(ReplyOptions{
+		Embeds:    []*discordgo.MessageEmbed{p.embed(LocalizeError(ctx, p.Catalog, err))},
+		Ephemeral: p.Ephemeral,
+	})
+}
@@ -2,8 +2,12 @@ package commandhandler
 
 import (
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -12,10 +16,17 @@ type MessageResolver func(ctx Context, arg string) (any, error)
 type SlashCommandResolver func(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error)
 
 type Resolver interface {
-	ResolveMessageOptions(cmd Command, ctx Context, args []string) (map[string]any, OptionError)
+	ResolveMessageOptions(cmd Command, ctx Context, args []Token) (map[string]any, OptionError)
 	ResolveSlashCommandOptions(cmd Command, ctx Context, args []*discordgo.ApplicationCommandInteractionDataOption) (map[string]any, OptionError)
 }
 
+// ModalResolver is implemented by resolvers that can resolve modal fields.
+// SimpleHandler falls back to the default SimpleResolver for resolvers that
+// do not implement it.
+type ModalResolver interface {
+	ResolveModalOptions(fields []Option, ctx Context, values map[string]string) (map[string]any, OptionError)
+}
+
 func NewResolver() Resolver {
 	return SimpleResolver{
 		MessageResolvers:      DefaultMessageResolvers(),
@@ -28,48 +39,190 @@ type SimpleResolver struct {
 	SlashCommandResolvers map[OptionType]SlashCommandResolver
 }
 
-func (r SimpleResolver) ResolveMessageOptions(cmd Command, ctx Context, args []string) (opts map[string]any, optErr OptionError) {
+func (r SimpleResolver) ResolveMessageOptions(cmd Command, ctx Context, args []Token) (opts map[string]any, optErr OptionError) {
 	opts = map[string]any{}
-	for i, opt := range cmd.Options {
-		if len(args)-1 < i {
-			if opt.Required {
-				opts[opt.Name] = ""
-				optErr = OptionError{opt.Name, RequiredOptionError}
-				return
+
+	named, positional, optErr := splitMessageArgs(cmd.Options, args)
+	if optErr.Err != nil {
+		return
+	}
+
+	attachments := []Token{}
+	if m, ok := ctx.(interface{ Message() *discordgo.Message }); ok {
+		for i := range m.Message().Attachments {
+			attachments = append(attachments, Token{Value: strconv.Itoa(i)})
+		}
+	}
+
+	for _, opt := range cmd.Options {
+		source := &positional
+		if opt.Type == AttachmentOptionType {
+			source = &attachments
+		}
+
+		values, ok := named[opt.Name]
+		if !ok {
+			if len(*source) == 0 {
+				if opt.Required {
+					opts[opt.Name] = ""
+					optErr = OptionError{opt.Name, RequiredOptionError}
+					return
+				}
+				if opt.Default != nil {
+					opts[opt.Name] = opt.Default
+				}
+				continue
+			}
+
+			switch {
+			case opt.Rest:
+				values, *source = []string{(*source)[0].Remainder}, nil
+			case opt.Variadic:
+				for _, token := range *source {
+					if token.Err != nil {
+						optErr = OptionError{opt.Name, token.Err}
+						return
+					}
+					values = append(values, token.Value)
+				}
+				*source = nil
+			default:
+				if err := (*source)[0].Err; err != nil {
+					optErr = OptionError{opt.Name, err}
+					return
+				}
+				values, *source = []string{(*source)[0].Value}, (*source)[1:]
 			}
-			continue
 		}
 
-		arg := args[i]
+		if !opt.Variadic {
+			values = values[len(values)-1:]
+		}
 
-		if len(opt.Choices) > 0 {
-			var err error
-			arg, err = resolveMessageOptionChoices(opt, arg)
+		resolved := []any{}
+		for _, arg := range values {
+			v, err := r.resolveMessageOption(opt, ctx, arg)
 			if err != nil {
 				opts[opt.Name] = arg
 				optErr = OptionError{opt.Name, err}
 				return
 			}
+			resolved = append(resolved, v)
 		}
 
-		if resolver, ok := r.MessageResolvers[opt.Type]; ok {
-			v, err := resolver(ctx, arg)
-			if err != nil {
-				opts[opt.Name] = arg
-				optErr = OptionError{opt.Name, fmt.Errorf("failed to resolve option '%s': %w", opt.Name, err)}
+		if opt.Variadic {
+			opts[opt.Name] = makeSlice(resolved)
+		} else {
+			opts[opt.Name] = resolved[0]
+		}
+	}
+
+	for _, token := range positional {
+		if token.Err != nil {
+			optErr = OptionError{Err: token.Err}
+			return
+		}
+	}
+	return
+}
+
+func (r SimpleResolver) resolveMessageOption(opt Option, ctx Context, arg string) (any, error) {
+	if len(opt.Choices) > 0 {
+		var err error
+		arg, err = resolveMessageOptionChoices(opt, arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resolver, ok := r.MessageResolvers[opt.Type]
+	if !ok {
+		return nil, fmt.Errorf("no resolver found for option type '%v'", opt.Type)
+	}
+
+	v, err := resolver(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve option '%s': %w", opt.Name, err)
+	}
+
+	return v, nil
+}
+
+func splitMessageArgs(options []Option, args []Token) (named map[string][]string, positional []Token, optErr OptionError) {
+	named = map[string][]string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i].Value
+
+		if args[i].Err != nil {
+			positional = append(positional, args[i])
+			continue
+		}
+
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			return
+		}
+
+		start := i
+		if name, ok := strings.CutPrefix(arg, "--"); ok {
+			name, value, hasValue := strings.Cut(name, "=")
+
+			opt, ok := findOption(options, name)
+			if !ok {
+				optErr = OptionError{name, InvalidOptionError}
 				return
 			}
 
-			opts[opt.Name] = v
-		} else {
-			opts[opt.Name] = arg
-			optErr = OptionError{opt.Name, fmt.Errorf("no resolver found for option type '%v'", opt.Type)}
-			return
+			if !hasValue {
+				if opt.Type == BooleanOptionType {
+					value = "true"
+					if i+1 < len(args) && (strings.EqualFold(args[i+1].Value, "true") || strings.EqualFold(args[i+1].Value, "false")) {
+						value = args[i+1].Value
+						i++
+					}
+				} else if i+1 < len(args) {
+					if args[i+1].Err != nil {
+						optErr = OptionError{name, args[i+1].Err}
+						return
+					}
+					value = args[i+1].Value
+					i++
+				} else {
+					optErr = OptionError{name, fmt.Errorf("no value given for option '%s'", name)}
+					return
+				}
+			}
+
+			named[opt.Name] = append(named[opt.Name], value)
+			cutRemainders(positional, args[start])
+			continue
 		}
+
+		if name, value, ok := strings.Cut(arg, ":"); ok {
+			if _, ok := findOption(options, name); ok {
+				named[name] = append(named[name], value)
+				cutRemainders(positional, args[i])
+				continue
+			}
+		}
+
+		positional = append(positional, args[i])
 	}
+
 	return
 }
 
+// cutRemainders ends the remainders of the positional tokens before the named
+// token, so a Rest option does not also consume named arguments.
+func cutRemainders(positional []Token, named Token) {
+	for i, token := range positional {
+		if rest, ok := strings.CutSuffix(token.Remainder, named.Remainder); ok {
+			positional[i].Remainder = strings.TrimRightFunc(rest, unicode.IsSpace)
+		}
+	}
+}
+
 func resolveMessageOptionChoices(opt Option, arg string) (string, error) {
 	for _, c := range opt.Choices {
 		if c.Name == arg {
@@ -81,7 +234,7 @@ func resolveMessageOptionChoices(opt Option, arg string) (string, error) {
 			case string:
 				arg = v
 			default:
-				panic(fmt.Sprintf("choice value cannot be of type %T", v))
+				return arg, fmt.Errorf("choice value cannot be of type %T", v)
 			}
 			return arg, nil
 		}
@@ -93,55 +246,139 @@ func resolveMessageOptionChoices(opt Option, arg string) (string, error) {
 func (r SimpleResolver) ResolveSlashCommandOptions(cmd Command, ctx Context, args []*discordgo.ApplicationCommandInteractionDataOption) (opts map[string]any, optErr OptionError) {
 	opts = map[string]any{}
 	for _, opt := range cmd.Options {
-		var found *discordgo.ApplicationCommandInteractionDataOption
-		for _, arg := range args {
-			if arg.Name == opt.Name {
-				found = arg
-			}
-		}
-		if found != nil {
-			if resolver, ok := r.SlashCommandResolvers[opt.Type]; ok {
-				v, err := resolver(ctx, *found)
-				if err != nil {
-					opts[opt.Name] = found
-					optErr = OptionError{opt.Name, fmt.Errorf("failed to resolve option '%s': %w", opt.Name, err)}
-					return
+		var found []*discordgo.ApplicationCommandInteractionDataOption
+		if opt.Variadic {
+			found = variadicSlashCommandArgs(opt.Name, args)
+		} else {
+			for _, arg := range args {
+				if arg.Name == opt.Name {
+					found = []*discordgo.ApplicationCommandInteractionDataOption{arg}
 				}
-				opts[opt.Name] = v
-			} else {
-				opts[opt.Name] = found
-				optErr = OptionError{opt.Name, fmt.Errorf("no resolver found for option type '%v'", opt.Type)}
+			}
+		}
+
+		if len(found) == 0 {
+			if opt.Default != nil {
+				opts[opt.Name] = opt.Default
+			}
+			continue
+		}
+
+		resolver, ok := r.SlashCommandResolvers[opt.Type]
+		if !ok {
+			opts[opt.Name] = found[0]
+			optErr = OptionError{opt.Name, fmt.Errorf("no resolver found for option type '%v'", opt.Type)}
+			return
+		}
+
+		resolved := []any{}
+		for _, arg := range found {
+			v, err := resolver(ctx, *arg)
+			if err != nil {
+				opts[opt.Name] = arg
+				optErr = OptionError{opt.Name, fmt.Errorf("failed to resolve option '%s': %w", opt.Name, err)}
+				return
+			}
+			resolved = append(resolved, v)
+		}
+
+		if opt.Variadic {
+			opts[opt.Name] = makeSlice(resolved)
+		} else {
+			opts[opt.Name] = resolved[0]
+		}
+	}
+
+	return opts, OptionError{}
+}
+
+func (r SimpleResolver) ResolveModalOptions(fields []Option, ctx Context, values map[string]string) (opts map[string]any, optErr OptionError) {
+	opts = map[string]any{}
+	for _, field := range fields {
+		value := values[field.Name]
+		if value == "" {
+			if field.Required {
+				opts[field.Name] = ""
+				optErr = OptionError{field.Name, RequiredOptionError}
 				return
 			}
+			if field.Default != nil {
+				opts[field.Name] = field.Default
+			}
+			continue
 		}
+
+		v, err := r.resolveMessageOption(field, ctx, value)
+		if err != nil {
+			opts[field.Name] = value
+			optErr = OptionError{field.Name, err}
+			return
+		}
+		opts[field.Name] = v
 	}
 
 	return opts, OptionError{}
 }
 
+func variadicSlashCommandArgs(name string, args []*discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandInteractionDataOption {
+	found := map[int]*discordgo.ApplicationCommandInteractionDataOption{}
+	for _, arg := range args {
+		if suffix, ok := strings.CutPrefix(arg.Name, name); ok {
+			if n, err := strconv.Atoi(suffix); err == nil && n > 0 {
+				found[n] = arg
+			}
+		}
+	}
+
+	ordered := []*discordgo.ApplicationCommandInteractionDataOption{}
+	for _, n := range slices.Sorted(maps.Keys(found)) {
+		ordered = append(ordered, found[n])
+	}
+	return ordered
+}
+
+func makeSlice(values []any) any {
+	t := reflect.TypeOf(values[0])
+	for _, v := range values {
+		if reflect.TypeOf(v) != t {
+			return values
+		}
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(t), 0, len(values))
+	for _, v := range values {
+		slice = reflect.Append(slice, reflect.ValueOf(v))
+	}
+	return slice.Interface()
+}
+
 func DefaultMessageResolvers() map[OptionType]MessageResolver {
 	return map[OptionType]MessageResolver{
-		StringOptionType:  stringResolver,
-		IntegerOptionType: integerResolver,
-		FloatOptionType:   floatResolver,
-		BooleanOptionType: booleanResolver,
-		UserOptionType:    userResolver,
-		MemberOptionType:  memberResolver,
-		ChannelOptionType: channelResolver,
-		RoleOptionType:    roleResolver,
+		StringOptionType:      stringResolver,
+		IntegerOptionType:     integerResolver,
+		FloatOptionType:       floatResolver,
+		BooleanOptionType:     booleanResolver,
+		UserOptionType:        userResolver,
+		MemberOptionType:      memberResolver,
+		ChannelOptionType:     channelResolver,
+		RoleOptionType:        roleResolver,
+		AttachmentOptionType:  attachmentResolver,
+		MentionableOptionType: mentionableResolver,
 	}
 }
 
 func DefaultSlashCommandResolvers() map[OptionType]SlashCommandResolver {
 	return map[OptionType]SlashCommandResolver{
-		StringOptionType:  slashCommandStringResolver,
-		IntegerOptionType: slashCommandIntegerResolver,
-		FloatOptionType:   slashCommandFloatResolver,
-		BooleanOptionType: slashCommandBooleanResolver,
-		UserOptionType:    slashCommandUserResolver,
-		MemberOptionType:  slashCommandMemberResolver,
-		ChannelOptionType: slashCommandChannelResolver,
-		RoleOptionType:    slashCommandRoleResolver,
+		StringOptionType:      slashCommandStringResolver,
+		IntegerOptionType:     slashCommandIntegerResolver,
+		FloatOptionType:       slashCommandFloatResolver,
+		BooleanOptionType:     slashCommandBooleanResolver,
+		UserOptionType:        slashCommandUserResolver,
+		MemberOptionType:      slashCommandMemberResolver,
+		ChannelOptionType:     slashCommandChannelResolver,
+		RoleOptionType:        slashCommandRoleResolver,
+		AttachmentOptionType:  slashCommandAttachmentResolver,
+		MentionableOptionType: slashCommandMentionableResolver,
 	}
 }
 
@@ -162,43 +399,91 @@ func booleanResolver(ctx Context, arg string) (any, error) {
 }
 
 func userResolver(ctx Context, arg string) (any, error) {
-	if strings.HasPrefix(arg, "<@") && strings.HasSuffix(arg, ">") {
-		arg = arg[2 : len(arg)-1]
+	if id, mention, ok := mentionID(arg, "<@!", "<@"); ok {
+		v, err := lookupUser(ctx, id)
+		if err == nil {
+			return v, nil
+		}
+		if mention {
+			return nil, err
+		}
 	}
-	v, err := ctx.Session().State.Member(ctx.GuildId(), arg)
+
+	v, err := findMember(ctx, strings.TrimPrefix(arg, "@"))
 	if err != nil {
-		return ctx.Session().User(arg)
+		return nil, err
 	}
 	return v.User, nil
 }
 
 func memberResolver(ctx Context, arg string) (any, error) {
-	if strings.HasPrefix(arg, "<@") && strings.HasSuffix(arg, ">") {
-		arg = arg[2 : len(arg)-1]
-	}
-	v, err := ctx.Session().State.Member(ctx.GuildId(), arg)
-	if err != nil {
-		return ctx.Session().GuildMember(ctx.GuildId(), arg)
+	if id, mention, ok := mentionID(arg, "<@!", "<@"); ok {
+		v, err := lookupMember(ctx, id)
+		if err == nil {
+			return v, nil
+		}
+		if mention {
+			return nil, err
+		}
 	}
-	return v, nil
+
+	return findMember(ctx, strings.TrimPrefix(arg, "@"))
 }
 
 func channelResolver(ctx Context, arg string) (any, error) {
-	if strings.HasPrefix(arg, "<#") && strings.HasSuffix(arg, ">") {
-		arg = arg[2 : len(arg)-1]
+	if id, mention, ok := mentionID(arg, "<#"); ok {
+		v, err := lookupChannel(ctx, id)
+		if err == nil {
+			return v, nil
+		}
+		if mention {
+			return nil, err
+		}
+	}
+
+	return findChannel(ctx, strings.TrimPrefix(arg, "#"))
+}
+
+func roleResolver(ctx Context, arg string) (any, error) {
+	if id, mention, ok := mentionID(arg, "<@&"); ok {
+		v, err := lookupRole(ctx, id)
+		if err == nil {
+			return v, nil
+		}
+		if mention {
+			return nil, err
+		}
+	}
+
+	return findRole(ctx, strings.TrimPrefix(arg, "@"))
+}
+
+func lookupUser(ctx Context, id string) (*discordgo.User, error) {
+	v, err := ctx.Session().State.Member(ctx.GuildId(), id)
+	if err != nil {
+		return ctx.Session().User(id)
 	}
-	v, err := ctx.Session().State.Channel(arg)
+	return v.User, nil
+}
+
+func lookupMember(ctx Context, id string) (*discordgo.Member, error) {
+	v, err := ctx.Session().State.Member(ctx.GuildId(), id)
 	if err != nil {
-		return ctx.Session().Channel(arg)
+		return ctx.Session().GuildMember(ctx.GuildId(), id)
 	}
 	return v, nil
 }
 
-func roleResolver(ctx Context, arg string) (any, error) {
-	if strings.HasPrefix(arg, "<@&") && strings.HasSuffix(arg, ">") {
-		arg = arg[3 : len(arg)-1]
+func lookupChannel(ctx Context, id string) (*discordgo.Channel, error) {
+	v, err := ctx.Session().State.Channel(id)
+	if err != nil {
+		return ctx.Session().Channel(id)
 	}
-	v, err := ctx.Session().State.Role(ctx.GuildId(), arg)
+	return v, nil
+}
+
+func lookupRole(ctx Context, id string) (*discordgo.Role, error) {
+	v, err := ctx.Session().State.Role(ctx.GuildId(), id)
 	if err != nil {
 		roles, err := ctx.Session().GuildRoles(ctx.GuildId())
 		if err != nil {
@@ -206,15 +491,152 @@ func roleResolver(ctx Context, arg string) (any, error) {
 		}
 
 		for _, role := range roles {
-			if role.ID == arg {
+			if role.ID == id {
 				return role, nil
 			}
 		}
-		return nil, fmt.Errorf("role with ID '%s' not found in guild", arg)
+		return nil, fmt.Errorf("role with ID '%s' not found in guild", id)
 	}
 	return v, nil
 }
 
+// mentionID strips any of the given mention prefixes from arg. It reports
+// whether arg was a mention and whether it can be looked up by ID at all; a
+// raw snowflake that is not found should still be tried as a name.
+func mentionID(arg string, prefixes ...string) (id string, mention bool, ok bool) {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(arg, prefix) && strings.HasSuffix(arg, ">") {
+			return arg[len(prefix) : len(arg)-1], true, true
+		}
+	}
+	_, err := strconv.ParseUint(arg, 10, 64)
+	return arg, false, err == nil
+}
+
+func findMember(ctx Context, name string) (*discordgo.Member, error) {
+	members := guildState(ctx, func(g *discordgo.Guild) []*discordgo.Member { return g.Members })
+	return fuzzyFind("member", name, members, func(m *discordgo.Member) []string {
+		names := []string{m.Nick, m.User.Username, m.User.GlobalName}
+		if m.User.Discriminator != "" && m.User.Discriminator != "0" {
+			names = append(names, m.User.Username+"#"+m.User.Discriminator)
+		}
+		return names
+	}, func(m *discordgo.Member) string {
+		if m.Nick != "" {
+			return fmt.Sprintf("%s (%s)", m.Nick, m.User.String())
+		}
+		return m.User.String()
+	})
+}
+
+func findChannel(ctx Context, name string) (*discordgo.Channel, error) {
+	channels := guildState(ctx, func(g *discordgo.Guild) []*discordgo.Channel { return g.Channels })
+	return fuzzyFind("channel", name, channels, func(c *discordgo.Channel) []string {
+		return []string{c.Name}
+	}, func(c *discordgo.Channel) string {
+		return "#" + c.Name
+	})
+}
+
+func findRole(ctx Context, name string) (*discordgo.Role, error) {
+	roles := guildState(ctx, func(g *discordgo.Guild) []*discordgo.Role { return g.Roles })
+	return fuzzyFind("role", name, roles, func(r *discordgo.Role) []string {
+		return []string{r.Name}
+	}, func(r *discordgo.Role) string {
+		return "@" + r.Name
+	})
+}
+
+func guildState[T any](ctx Context, items func(g *discordgo.Guild) []T) []T {
+	state := ctx.Session().State
+	if state == nil || ctx.GuildId() == "" {
+		return nil
+	}
+
+	g, err := state.Guild(ctx.GuildId())
+	if err != nil {
+		return nil
+	}
+
+	state.RLock()
+	defer state.RUnlock()
+	return slices.Clone(items(g))
+}
+
+// fuzzyFind matches name against the names of each item, preferring exact
+// matches over case-insensitive ones and those over case-insensitive prefix
+// matches. Several matches within the same tier are reported as an
+// AmbiguousArgumentError.
+func fuzzyFind[T any](kind string, name string, items []T, names func(T) []string, label func(T) string) (T, error) {
+	var zero T
+	if name == "" {
+		return zero, fmt.Errorf("%s '%s' not found", kind, name)
+	}
+
+	lower := strings.ToLower(name)
+	tiers := make([][]T, 3)
+	for _, item := range items {
+		tier := len(tiers)
+		for _, n := range names(item) {
+			switch {
+			case n == "":
+			case n == name:
+				tier = min(tier, 0)
+			case strings.ToLower(n) == lower:
+				tier = min(tier, 1)
+			case strings.HasPrefix(strings.ToLower(n), lower):
+				tier = min(tier, 2)
+			}
+		}
+		if tier < len(tiers) {
+			tiers[tier] = append(tiers[tier], item)
+		}
+	}
+
+	for _, matches := range tiers {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		}
+
+		candidates := make([]string, len(matches))
+		for i, m := range matches {
+			candidates[i] = label(m)
+		}
+		return zero, AmbiguousArgumentError{Arg: name, Candidates: candidates}
+	}
+	return zero, fmt.Errorf("%s '%s' not found", kind, name)
+}
+
+func attachmentResolver(ctx Context, arg string) (any, error) {
+	m, ok := ctx.(interface{ Message() *discordgo.Message })
+	if !ok {
+		return nil, fmt.Errorf("attachments are not available in this context")
+	}
+
+	i, err := strconv.Atoi(arg)
+	if err != nil || i < 0 || i >= len(m.Message().Attachments) {
+		return nil, fmt.Errorf("attachment '%s' not found", arg)
+	}
+	return m.Message().Attachments[i], nil
+}
+
+func mentionableResolver(ctx Context, arg string) (any, error) {
+	if strings.HasPrefix(arg, "<@&") {
+		return roleResolver(ctx, arg)
+	}
+	if strings.HasPrefix(arg, "<@") {
+		return userResolver(ctx, arg)
+	}
+
+	if role, err := roleResolver(ctx, arg); err == nil {
+		return role, nil
+	}
+	return userResolver(ctx, arg)
+}
+
 func slashCommandIntegerResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
 	return arg.IntValue(), nil
 }
@@ -232,17 +654,85 @@ func slashCommandBooleanResolver(ctx Context, arg discordgo.ApplicationCommandIn
 }
 
 func slashCommandUserResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
-	return userResolver(ctx, arg.Value.(string))
+	id := arg.Value.(string)
+	if resolved := resolvedData(ctx); resolved != nil {
+		if user, ok := resolved.Users[id]; ok {
+			return user, nil
+		}
+	}
+	return userResolver(ctx, id)
 }
 
 func slashCommandMemberResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
-	return memberResolver(ctx, arg.Value.(string))
+	id := arg.Value.(string)
+	if resolved := resolvedData(ctx); resolved != nil {
+		if member, ok := resolved.Members[id]; ok {
+			m := *member
+			m.GuildID = ctx.GuildId()
+			if m.User == nil {
+				m.User = resolved.Users[id]
+			}
+			if m.User != nil {
+				return &m, nil
+			}
+		}
+	}
+	return memberResolver(ctx, id)
 }
 
+// The resolved channel's permissions are dropped by discordgo and are not
+// available here. SlashCommandContext.ChannelPermissions only recomputes them
+// from state or REST.
 func slashCommandChannelResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
-	return channelResolver(ctx, arg.Value.(string))
+	id := arg.Value.(string)
+	if resolved := resolvedData(ctx); resolved != nil {
+		if channel, ok := resolved.Channels[id]; ok {
+			c := *channel
+			if c.GuildID == "" {
+				c.GuildID = ctx.GuildId()
+			}
+			return &c, nil
+		}
+	}
+	return channelResolver(ctx, id)
 }
 
 func slashCommandRoleResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
-	return roleResolver(ctx, arg.Value.(string))
+	id := arg.Value.(string)
+	if resolved := resolvedData(ctx); resolved != nil {
+		if role, ok := resolved.Roles[id]; ok {
+			return role, nil
+		}
+	}
+	return roleResolver(ctx, id)
+}
+
+func slashCommandAttachmentResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
+	if resolved := resolvedData(ctx); resolved != nil {
+		if attachment, ok := resolved.Attachments[arg.Value.(string)]; ok {
+			return attachment, nil
+		}
+	}
+	return nil, fmt.Errorf("attachment '%v' not found", arg.Value)
+}
+
+func slashCommandMentionableResolver(ctx Context, arg discordgo.ApplicationCommandInteractionDataOption) (any, error) {
+	id := arg.Value.(string)
+	if resolved := resolvedData(ctx); resolved != nil {
+		if user, ok := resolved.Users[id]; ok {
+			return user, nil
+		}
+		if role, ok := resolved.Roles[id]; ok {
+			return role, nil
+		}
+	}
+	return mentionableResolver(ctx, id)
+}
+
+func resolvedData(ctx Context) *discordgo.ApplicationCommandInteractionDataResolved {
+	i, ok := ctx.(interface{ Interaction() *discordgo.Interaction })
+	if !ok || i.Interaction().Type != discordgo.InteractionApplicationCommand && i.Interaction().Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return nil
+	}
+	return i.Interaction().ApplicationCommandData().Resolved
 }
@@ -0,0 +1,136 @@
+package commandhandler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func resolveMessage(t *testing.T, options []Option, input string) (map[string]any, OptionError) {
+	t.Helper()
+
+	tokens, err := tokenizeLenient(SimpleTokenizer{}, input)
+	if err != nil {
+		t.Fatalf("Tokenize(%q) returned error: %v", input, err)
+	}
+
+	return NewResolver().ResolveMessageOptions(Command{Options: options}, nil, tokens)
+}
+
+func TestResolveMessageOptionsNamed(t *testing.T) {
+	options := []Option{
+		{Name: "user", Type: StringOptionType},
+		{Name: "reason", Type: StringOptionType},
+		{Name: "days", Type: IntegerOptionType},
+		{Name: "silent", Type: BooleanOptionType},
+	}
+
+	tests := []struct {
+		input string
+		want  map[string]any
+	}{
+		{"bob spam 7", map[string]any{"user": "bob", "reason": "spam", "days": int64(7)}},
+		{"--user bob", map[string]any{"user": "bob"}},
+		{"--user=bob --days=3", map[string]any{"user": "bob", "days": int64(3)}},
+		{"user:bob days:3", map[string]any{"user": "bob", "days": int64(3)}},
+		{`bob reason:"spam links"`, map[string]any{"user": "bob", "reason": "spam links"}},
+		{`bob --reason="spam links"`, map[string]any{"user": "bob", "reason": "spam links"}},
+		{`bob --reason "spam links"`, map[string]any{"user": "bob", "reason": "spam links"}},
+		{"--days 3 bob spam", map[string]any{"user": "bob", "reason": "spam", "days": int64(3)}},
+		{"bob --days 3 spam", map[string]any{"user": "bob", "reason": "spam", "days": int64(3)}},
+		{"--silent bob", map[string]any{"user": "bob", "silent": true}},
+		{"--silent false bob", map[string]any{"user": "bob", "silent": false}},
+		{"--silent 1", map[string]any{"user": "1", "silent": true}},
+		{"--silent=0 bob", map[string]any{"user": "bob", "silent": false}},
+		{"-- --days bob", map[string]any{"user": "--days", "reason": "bob"}},
+		{"bob -- reason:spam", map[string]any{"user": "bob", "reason": "reason:spam"}},
+		{"other:value", map[string]any{"user": "other:value"}},
+	}
+
+	for _, tt := range tests {
+		got, optErr := resolveMessage(t, options, tt.input)
+		if optErr.Err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, optErr.Err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResolveMessageOptionsNamedErrors(t *testing.T) {
+	options := []Option{
+		{Name: "user", Type: StringOptionType},
+		{Name: "days", Type: IntegerOptionType},
+	}
+
+	tests := []struct {
+		input string
+		opt   string
+		err   error
+	}{
+		{"--unknown bob", "unknown", InvalidOptionError},
+		{"bob --days", "days", nil},
+	}
+
+	for _, tt := range tests {
+		_, optErr := resolveMessage(t, options, tt.input)
+		if optErr.Err == nil || optErr.Opt != tt.opt {
+			t.Errorf("%q: got error %v on %q, want error on %q", tt.input, optErr.Err, optErr.Opt, tt.opt)
+			continue
+		}
+		if tt.err != nil && !errors.Is(optErr.Err, tt.err) {
+			t.Errorf("%q: got error %v, want %v", tt.input, optErr.Err, tt.err)
+		}
+	}
+}
+
+func TestResolveMessageOptionsRest(t *testing.T) {
+	options := []Option{
+		{Name: "channel", Type: StringOptionType},
+		{Name: "text", Type: StringOptionType, Rest: true},
+	}
+
+	tests := []struct {
+		input string
+		want  map[string]any
+	}{
+		{"general hello world", map[string]any{"channel": "general", "text": "hello world"}},
+		{"general  hello\n  world ", map[string]any{"channel": "general", "text": "hello\n  world "}},
+		{`general "hello  world"`, map[string]any{"channel": "general", "text": `"hello  world"`}},
+		{"hello  world --channel general", map[string]any{"channel": "general", "text": "hello  world"}},
+		{"--channel general hello world", map[string]any{"channel": "general", "text": "hello world"}},
+		{"channel:general hello world", map[string]any{"channel": "general", "text": "hello world"}},
+		{`general he said "hi`, map[string]any{"channel": "general", "text": `he said "hi`}},
+		{`general "hi`, map[string]any{"channel": "general", "text": `"hi`}},
+		{"general stray ` backtick", map[string]any{"channel": "general", "text": "stray ` backtick"}},
+	}
+
+	for _, tt := range tests {
+		got, optErr := resolveMessage(t, options, tt.input)
+		if optErr.Err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, optErr.Err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResolveMessageOptionsTokenizeError(t *testing.T) {
+	options := []Option{
+		{Name: "user", Type: StringOptionType},
+		{Name: "reason", Type: StringOptionType},
+	}
+
+	for _, input := range []string{`bob "spam`, `"bob`, `bob reason:"spam`, `bob --reason "spam`, "bob `spam"} {
+		_, optErr := resolveMessage(t, options, input)
+
+		var tokErr TokenizeError
+		if !errors.As(optErr.Err, &tokErr) {
+			t.Errorf("%q: got error %v, want TokenizeError", input, optErr.Err)
+		}
+	}
+}
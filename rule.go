@@ -1,8 +1,7 @@
 package commandhandler
 
 import (
-	"errors"
-	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -21,7 +20,7 @@ func (r MaxInt) Test(value any) error {
 	if value.(int64) <= r.Max {
 		return nil
 	}
-	return fmt.Errorf("value exceeds the maximum allowed of %d", r.Max)
+	return RuleError{"max_int", map[string]any{"max": r.Max}}
 }
 
 type MaxFloat struct {
@@ -32,7 +31,7 @@ func (r MaxFloat) Test(value any) error {
 	if value.(float64) <= r.Max {
 		return nil
 	}
-	return fmt.Errorf("value exceeds the maximum allowed of %.2f", r.Max)
+	return RuleError{"max_float", map[string]any{"max": r.Max}}
 }
 
 type MaxString struct {
@@ -43,7 +42,7 @@ func (r MaxString) Test(value any) error {
 	if len(value.(string)) <= r.Max {
 		return nil
 	}
-	return fmt.Errorf("string length exceeds the maximum allowed of %d", r.Max)
+	return RuleError{"max_string", map[string]any{"max": r.Max}}
 }
 
 type MinInt struct {
@@ -54,7 +53,7 @@ func (r MinInt) Test(value any) error {
 	if value.(int64) >= r.Min {
 		return nil
 	}
-	return fmt.Errorf("value is less than the minimum allowed of %d", r.Min)
+	return RuleError{"min_int", map[string]any{"min": r.Min}}
 }
 
 type MinFloat struct {
@@ -65,7 +64,7 @@ func (r MinFloat) Test(value any) error {
 	if value.(float64) >= r.Min {
 		return nil
 	}
-	return fmt.Errorf("value is less than the minimum allowed of %.2f", r.Min)
+	return RuleError{"min_float", map[string]any{"min": r.Min}}
 }
 
 type MinString struct {
@@ -76,7 +75,7 @@ func (r MinString) Test(value any) error {
 	if len(value.(string)) >= r.Min {
 		return nil
 	}
-	return fmt.Errorf("string length is less than the minimum allowed of %d", r.Min)
+	return RuleError{"min_string", map[string]any{"min": r.Min}}
 }
 
 type Uppercase struct{}
@@ -85,7 +84,7 @@ func (Uppercase) Test(value any) error {
 	if value.(string) == strings.ToUpper(value.(string)) {
 		return nil
 	}
-	return errors.New("value must be uppercase")
+	return RuleError{"uppercase", nil}
 }
 
 type Lowercase struct{}
@@ -94,7 +93,7 @@ func (Lowercase) Test(value any) error {
 	if value.(string) == strings.ToLower(value.(string)) {
 		return nil
 	}
-	return errors.New("value must be lowercase")
+	return RuleError{"lowercase", nil}
 }
 
 type ChannelType struct {
@@ -105,14 +104,36 @@ func (r ChannelType) Test(value any) error {
 	if slices.Contains(r.Types, value.(*discordgo.Channel).Type) {
 		return nil
 	}
-	return fmt.Errorf("channel type '%v' is not allowed", value.(*discordgo.Channel).Type)
+	return RuleError{"channel_type", map[string]any{"type": value.(*discordgo.Channel).Type}}
+}
+
+type MinItems struct {
+	Min int
+}
+
+func (r MinItems) Test(value any) error {
+	if reflect.ValueOf(value).Len() >= r.Min {
+		return nil
+	}
+	return RuleError{"min_items", map[string]any{"min": r.Min}}
+}
+
+type MaxItems struct {
+	Max int
+}
+
+func (r MaxItems) Test(value any) error {
+	if reflect.ValueOf(value).Len() <= r.Max {
+		return nil
+	}
+	return RuleError{"max_items", map[string]any{"max": r.Max}}
 }
 
 func Validate(opts []Option, values map[string]any) OptionError {
 	for _, opt := range opts {
 		for _, rule := range opt.Rules {
 			if v, ok := values[opt.Name]; ok {
-				if err := rule.Test(v); err != nil {
+				if err := testRule(opt, rule, v); err != nil {
 					return OptionError{opt.Name, err}
 				}
 			}
@@ -120,3 +141,22 @@ func Validate(opts []Option, values map[string]any) OptionError {
 	}
 	return OptionError{}
 }
+
+func testRule(opt Option, rule Rule, value any) error {
+	switch rule.(type) {
+	case MinItems, MaxItems:
+		return rule.Test(value)
+	}
+
+	if !opt.Variadic {
+		return rule.Test(value)
+	}
+
+	items := reflect.ValueOf(value)
+	for i := 0; i < items.Len(); i++ {
+		if err := rule.Test(items.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
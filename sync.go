@@ -0,0 +1,204 @@
+package commandhandler
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type SyncAction uint8
+
+const (
+	CreateSyncAction SyncAction = 0
+	EditSyncAction   SyncAction = 1
+	DeleteSyncAction SyncAction = 2
+)
+
+func (a SyncAction) String() string {
+	switch a {
+	case CreateSyncAction:
+		return "create"
+	case EditSyncAction:
+		return "edit"
+	default:
+		return "delete"
+	}
+}
+
+type SyncChange struct {
+	Action  SyncAction
+	ID      string
+	Command *discordgo.ApplicationCommand
+}
+
+type SyncOptions struct {
+	DryRun        bool
+	BulkOverwrite bool
+}
+
+func Sync(s *discordgo.Session, appID string, guildID string, cmds []Command, builder Builder, opts SyncOptions) ([]SyncChange, error) {
+	existing, err := fetchCommands(s, appID, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := []*discordgo.ApplicationCommand{}
+	for _, cmd := range cmds {
+		desired = append(desired, builder.Build(cmd))
+	}
+
+	changes := diffCommands(existing, desired)
+
+	if opts.DryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	if opts.BulkOverwrite {
+		_, err := s.ApplicationCommandBulkOverwrite(appID, guildID, desired)
+		return changes, err
+	}
+
+	for _, change := range changes {
+		switch change.Action {
+		case CreateSyncAction:
+			_, err = s.ApplicationCommandCreate(appID, guildID, change.Command)
+		case EditSyncAction:
+			_, err = s.ApplicationCommandEdit(appID, guildID, change.ID, change.Command)
+		case DeleteSyncAction:
+			err = s.ApplicationCommandDelete(appID, guildID, change.ID)
+		}
+		if err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
+
+// fetchCommands lists the registered commands including their localizations,
+// which Discord omits unless explicitly requested.
+func fetchCommands(s *discordgo.Session, appID string, guildID string) ([]*discordgo.ApplicationCommand, error) {
+	endpoint := discordgo.EndpointApplicationGlobalCommands(appID)
+	if guildID != "" {
+		endpoint = discordgo.EndpointApplicationGuildCommands(appID, guildID)
+	}
+
+	body, err := s.RequestWithBucketID("GET", endpoint+"?with_localizations=true", nil, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := []*discordgo.ApplicationCommand{}
+	err = json.Unmarshal(body, &cmds)
+	return cmds, err
+}
+
+func diffCommands(existing []*discordgo.ApplicationCommand, desired []*discordgo.ApplicationCommand) []SyncChange {
+	changes := []SyncChange{}
+
+	type key struct {
+		Type discordgo.ApplicationCommandType
+		Name string
+	}
+
+	keyOf := func(cmd *discordgo.ApplicationCommand) key {
+		t := cmd.Type
+		if t == 0 {
+			t = discordgo.ChatApplicationCommand
+		}
+		return key{t, cmd.Name}
+	}
+
+	current := map[key]*discordgo.ApplicationCommand{}
+	for _, cmd := range existing {
+		current[keyOf(cmd)] = cmd
+	}
+
+	for _, cmd := range desired {
+		k := keyOf(cmd)
+		old, ok := current[k]
+		if !ok {
+			changes = append(changes, SyncChange{Action: CreateSyncAction, Command: cmd})
+			continue
+		}
+		delete(current, k)
+
+		if !reflect.DeepEqual(normalizeCommand(old), normalizeCommand(cmd)) {
+			changes = append(changes, SyncChange{Action: EditSyncAction, ID: old.ID, Command: cmd})
+		}
+	}
+
+	for _, cmd := range existing {
+		if _, ok := current[keyOf(cmd)]; ok {
+			changes = append(changes, SyncChange{Action: DeleteSyncAction, ID: cmd.ID, Command: cmd})
+		}
+	}
+
+	return changes
+}
+
+func normalizeCommand(cmd *discordgo.ApplicationCommand) any {
+	c := *cmd
+	c.ID, c.ApplicationID, c.GuildID, c.Version = "", "", "", ""
+	if c.Type == 0 {
+		c.Type = discordgo.ChatApplicationCommand
+	}
+	// Discord reports these defaults on every command while Build leaves them
+	// unset; false values are dropped by pruneJSON.
+	if c.DMPermission != nil && *c.DMPermission {
+		c.DMPermission = nil
+	}
+	if c.DefaultPermission != nil && *c.DefaultPermission {
+		c.DefaultPermission = nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	return pruneJSON(v)
+}
+
+func pruneJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			value = pruneJSON(value)
+			if value == nil {
+				delete(v, k)
+			} else {
+				v[k] = value
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, value := range v {
+			v[i] = pruneJSON(value)
+		}
+		return v
+	case bool:
+		if !v {
+			return nil
+		}
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+		return v
+	default:
+		return v
+	}
+}
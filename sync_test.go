@@ -0,0 +1,119 @@
+package commandhandler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func registeredCommands(t *testing.T, raw string) []*discordgo.ApplicationCommand {
+	t.Helper()
+
+	cmds := []*discordgo.ApplicationCommand{}
+	if err := json.Unmarshal([]byte(raw), &cmds); err != nil {
+		t.Fatal(err)
+	}
+	return cmds
+}
+
+func TestDiffCommandsUnchanged(t *testing.T) {
+	run := func(Context, map[string]any) {}
+	builder := NewBuilder()
+
+	tests := []struct {
+		name     string
+		existing string
+		cmd      Command
+	}{
+		{
+			"discord defaults",
+			`[{"id":"1","application_id":"2","version":"3","type":1,"name":"ping","description":"Ping",
+				"dm_permission":true,"default_permission":true,"nsfw":false,"default_member_permissions":null}]`,
+			Command{Name: "ping", Description: "Ping", Run: run},
+		},
+		{
+			"type 0 and missing type",
+			`[{"id":"1","name":"ping","description":"Ping"}]`,
+			Command{Name: "ping", Description: "Ping", Run: run},
+		},
+		{
+			"empty localizations",
+			`[{"id":"1","type":1,"name":"ping","description":"Ping","name_localizations":{},"description_localizations":null,
+				"options":[{"type":3,"name":"text","description":"Text","name_localizations":{},"required":false}]}]`,
+			Command{Name: "ping", Description: "Ping", Options: []Option{{Name: "text", Description: "Text"}}, Run: run},
+		},
+		{
+			"localizations",
+			`[{"id":"1","type":1,"name":"ping","description":"Ping","name_localizations":{"de":"pingen"}}]`,
+			Command{Name: "ping", Description: "Ping", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "pingen"}, Run: run},
+		},
+		{
+			"integer and float choices",
+			`[{"id":"1","type":1,"name":"pick","description":"Pick","options":[
+				{"type":4,"name":"int","description":"Int","choices":[{"name":"Five","value":5}]},
+				{"type":10,"name":"float","description":"Float","choices":[{"name":"Half","value":0.5}]}]}]`,
+			Command{Name: "pick", Description: "Pick", Options: []Option{
+				{Name: "int", Description: "Int", Type: IntegerOptionType, Choices: []Choice{{Name: "Five", Value: int64(5)}}},
+				{Name: "float", Description: "Float", Type: FloatOptionType, Choices: []Choice{{Name: "Half", Value: 0.5}}},
+			}, Run: run},
+		},
+		{
+			"permissions",
+			`[{"id":"1","type":1,"name":"ban","description":"Ban","default_member_permissions":"4"}]`,
+			Command{Name: "ban", Description: "Ban", Permissions: discordgo.PermissionBanMembers, Run: run},
+		},
+		{
+			"context menu",
+			`[{"id":"1","type":2,"name":"Inspect","description":""}]`,
+			Command{Kind: UserCommandKind, Name: "Inspect", Run: run},
+		},
+	}
+
+	for _, tt := range tests {
+		desired := []*discordgo.ApplicationCommand{builder.Build(tt.cmd)}
+		if changes := diffCommands(registeredCommands(t, tt.existing), desired); len(changes) != 0 {
+			t.Errorf("%s: got %d changes, want none", tt.name, len(changes))
+		}
+	}
+}
+
+func TestDiffCommandsChanges(t *testing.T) {
+	run := func(Context, map[string]any) {}
+	builder := NewBuilder()
+
+	existing := registeredCommands(t, `[
+		{"id":"1","type":1,"name":"ping","description":"Ping"},
+		{"id":"2","type":1,"name":"echo","description":"Echo"},
+		{"id":"3","type":1,"name":"stale","description":"Stale"},
+		{"id":"4","type":2,"name":"echo","description":""}]`)
+
+	desired := []*discordgo.ApplicationCommand{
+		builder.Build(Command{Name: "ping", Description: "Ping", Run: run}),
+		builder.Build(Command{Name: "echo", Description: "Echo a message", Run: run}),
+		builder.Build(Command{Name: "new", Description: "New", Run: run}),
+		builder.Build(Command{Kind: UserCommandKind, Name: "echo", Run: run}),
+	}
+
+	changes := diffCommands(existing, desired)
+
+	want := []struct {
+		action SyncAction
+		id     string
+		name   string
+	}{
+		{EditSyncAction, "2", "echo"},
+		{CreateSyncAction, "", "new"},
+		{DeleteSyncAction, "3", "stale"},
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Action != w.action || c.ID != w.id || c.Command.Name != w.name {
+			t.Errorf("change %d: got %s %q (%s), want %s %q (%s)", i, c.Action, c.Command.Name, c.ID, w.action, w.name, w.id)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package commandhandler
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Token struct {
+	Value string
+	// Remainder is the unprocessed input from the start of the token to the end.
+	Remainder string
+	// Err is set on a trailing token whose input could not be tokenized; its
+	// Value is then the raw input. Only a Rest option may consume it.
+	Err error
+}
+
+type Tokenizer interface {
+	Tokenize(input string) ([]Token, error)
+}
+
+func NewTokenizer() Tokenizer {
+	return SimpleTokenizer{}
+}
+
+type SimpleTokenizer struct{}
+
+func (SimpleTokenizer) Tokenize(input string) ([]Token, error) {
+	tokens := []Token{}
+
+	var value strings.Builder
+	start := -1
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				tokens = append(tokens, Token{Value: value.String(), Remainder: input[start:]})
+				value.Reset()
+				start = -1
+			}
+			i += size
+			continue
+		}
+
+		if start < 0 {
+			start = i
+		}
+
+		// Quotes only open a quoted span at the start of a token or right after a
+		// name: or --name= prefix, so apostrophes inside words are kept as literal
+		// characters. An unmatched single quote is an apostrophe too (e.g. 'tis),
+		// not an unterminated quote.
+		quotable := i == start || isNamedPrefix(input[start:i])
+
+		switch {
+		case r == '\\':
+			i += size
+			if i < len(input) {
+				r, size = utf8.DecodeRuneInString(input[i:])
+				value.WriteRune(r)
+				i += size
+			} else {
+				value.WriteRune('\\')
+			}
+		case r == '"' && quotable:
+			end := i + size
+			for ; end < len(input) && input[end] != '"'; end++ {
+				if input[end] == '\\' && end+1 < len(input) && (input[end+1] == '"' || input[end+1] == '\\') {
+					end++
+				}
+				value.WriteByte(input[end])
+			}
+			if end >= len(input) {
+				return nil, TokenizeError{i, UnterminatedQuoteError}
+			}
+			i = end + 1
+		case r == '\'' && quotable && strings.IndexByte(input[i+size:], '\'') >= 0:
+			end := strings.IndexByte(input[i+size:], '\'')
+			value.WriteString(input[i+size : i+size+end])
+			i += size + end + 1
+		case r == '`':
+			fence := "`"
+			if strings.HasPrefix(input[i:], "```") {
+				fence = "```"
+			}
+			end := strings.Index(input[i+len(fence):], fence)
+			if end < 0 {
+				return nil, TokenizeError{i, UnterminatedCodeError}
+			}
+			end += i + 2*len(fence)
+			value.WriteString(input[i:end])
+			i = end
+		default:
+			value.WriteRune(r)
+			i += size
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, Token{Value: value.String(), Remainder: input[start:]})
+	}
+
+	return tokens, nil
+}
+
+// tokenizeLenient is like tokenizer.Tokenize, but on a TokenizeError it
+// returns the tokens before the failing one followed by a token carrying the
+// error, so the error only surfaces if that part of the input is used.
+func tokenizeLenient(tokenizer Tokenizer, input string) ([]Token, error) {
+	tokens, err := tokenizer.Tokenize(input)
+
+	var tokErr TokenizeError
+	if !errors.As(err, &tokErr) || tokErr.Pos > len(input) {
+		return tokens, err
+	}
+
+	start := strings.LastIndexFunc(input[:tokErr.Pos], unicode.IsSpace)
+	if start >= 0 {
+		_, size := utf8.DecodeRuneInString(input[start:])
+		start += size
+	} else {
+		start = 0
+	}
+
+	tokens, err = tokenizer.Tokenize(input[:start])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tokens {
+		tokens[i].Remainder += input[start:]
+	}
+	return append(tokens, Token{Value: input[start:], Remainder: input[start:], Err: tokErr}), nil
+}
+
+func isNamedPrefix(s string) bool {
+	name, ok := strings.CutSuffix(s, ":")
+	if !ok {
+		if name, ok = strings.CutSuffix(s, "="); !ok {
+			return false
+		}
+		if name, ok = strings.CutPrefix(name, "--"); !ok {
+			return false
+		}
+	}
+	return name != "" && !strings.ContainsAny(name, ":=\"'\\`")
+}
@@ -0,0 +1,88 @@
+package commandhandler
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSimpleTokenizer(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{}},
+		{"say hello   world", []string{"say", "hello", "world"}},
+		{"say hello\nworld", []string{"say", "hello", "world"}},
+		{"say it's fine", []string{"say", "it's", "fine"}},
+		{"say 'tis fine", []string{"say", "'tis", "fine"}},
+		{"say don't won't", []string{"say", "don't", "won't"}},
+		{`say "hello world" again`, []string{"say", "hello world", "again"}},
+		{`say 'hello world' again`, []string{"say", "hello world", "again"}},
+		{`say "it's fine"`, []string{"say", "it's fine"}},
+		{`say a"b c`, []string{"say", `a"b`, "c"}},
+		{`ban bob reason:"spam links"`, []string{"ban", "bob", "reason:spam links"}},
+		{`ban bob --reason="spam links"`, []string{"ban", "bob", "--reason=spam links"}},
+		{`ban bob reason:'spam links'`, []string{"ban", "bob", "reason:spam links"}},
+		{`say a=b"c d"`, []string{"say", `a=b"c`, `d"`}},
+		{`say hello\ world`, []string{"say", "hello world"}},
+		{`say \"quoted\"`, []string{"say", `"quoted"`}},
+		{`say "a \"b\" c"`, []string{"say", `a "b" c`}},
+		{`say trailing\`, []string{"say", `trailing\`}},
+		{"eval `a b`", []string{"eval", "`a b`"}},
+		{"eval ```go\nfmt.Println(1)\n``` after", []string{"eval", "```go\nfmt.Println(1)\n```", "after"}},
+	}
+
+	for _, tt := range tests {
+		tokens, err := SimpleTokenizer{}.Tokenize(tt.input)
+		if err != nil {
+			t.Errorf("Tokenize(%q) returned error: %v", tt.input, err)
+			continue
+		}
+
+		got := make([]string, len(tokens))
+		for i, token := range tokens {
+			got[i] = token.Value
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSimpleTokenizerRemainder(t *testing.T) {
+	tokens, err := SimpleTokenizer{}.Tokenize("say  hello\n  world")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tokens[1].Remainder, "hello\n  world"; got != want {
+		t.Errorf("Remainder = %q, want %q", got, want)
+	}
+}
+
+func TestSimpleTokenizerErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		err   error
+	}{
+		{`say "hello`, 4, UnterminatedQuoteError},
+		{`say it's "fine`, 9, UnterminatedQuoteError},
+		{"eval `a b", 5, UnterminatedCodeError},
+		{"eval ```a b``", 5, UnterminatedCodeError},
+	}
+
+	for _, tt := range tests {
+		_, err := SimpleTokenizer{}.Tokenize(tt.input)
+
+		var tokErr TokenizeError
+		if !errors.As(err, &tokErr) {
+			t.Errorf("Tokenize(%q) error = %v, want TokenizeError", tt.input, err)
+			continue
+		}
+		if tokErr.Pos != tt.pos || !errors.Is(err, tt.err) {
+			t.Errorf("Tokenize(%q) error = %v at %d, want %v at %d", tt.input, tokErr.Err, tokErr.Pos, tt.err, tt.pos)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package commandhandler
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Typed derives cmd's options from the fields of T and decodes the resolved
+// values into T before calling run. Fields are configured with the tags
+// option ("name,required,rest"), description, min, max and choices
+// ("Name=value,Other=value").
+func Typed[T any](cmd Command, run func(ctx Context, args T)) Command {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("typed command '%s' requires a struct type, got %v", cmd.Name, t))
+	}
+
+	fields := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("option") == "-" {
+			continue
+		}
+
+		opt, err := typedOption(field)
+		if err != nil {
+			panic(fmt.Sprintf("typed command '%s': field %s: %v", cmd.Name, field.Name, err))
+		}
+
+		cmd.Options = append(cmd.Options, opt)
+		fields[opt.Name] = i
+	}
+
+	cmd.Run = nil
+	cmd.RunE = func(ctx Context, opts map[string]any) error {
+		var args T
+		v := reflect.ValueOf(&args).Elem()
+
+		for name, i := range fields {
+			value, ok := opts[name]
+			if !ok {
+				continue
+			}
+			if err := assignTyped(v.Field(i), value); err != nil {
+				return fmt.Errorf("failed to decode option '%s': %w", name, err)
+			}
+		}
+
+		run(ctx, args)
+		return nil
+	}
+
+	return cmd
+}
+
+var (
+	userType       = reflect.TypeOf((*discordgo.User)(nil))
+	memberType     = reflect.TypeOf((*discordgo.Member)(nil))
+	channelType    = reflect.TypeOf((*discordgo.Channel)(nil))
+	roleType       = reflect.TypeOf((*discordgo.Role)(nil))
+	attachmentType = reflect.TypeOf((*discordgo.MessageAttachment)(nil))
+)
+
+func typedOption(field reflect.StructField) (Option, error) {
+	opt := Option{
+		Name:        snakeCase(field.Name),
+		Description: field.Tag.Get("description"),
+	}
+
+	name, flags, _ := strings.Cut(field.Tag.Get("option"), ",")
+	if name != "" {
+		opt.Name = name
+	}
+	for _, flag := range strings.Split(flags, ",") {
+		switch flag {
+		case "required":
+			opt.Required = true
+		case "rest":
+			opt.Rest = true
+		case "":
+		default:
+			return opt, fmt.Errorf("unknown option flag '%s'", flag)
+		}
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Slice {
+		opt.Variadic = true
+		t = t.Elem()
+	}
+
+	switch {
+	case t == userType:
+		opt.Type = UserOptionType
+	case t == memberType:
+		opt.Type = MemberOptionType
+	case t == channelType:
+		opt.Type = ChannelOptionType
+	case t == roleType:
+		opt.Type = RoleOptionType
+	case t == attachmentType:
+		opt.Type = AttachmentOptionType
+	case t.Kind() == reflect.String:
+		opt.Type = StringOptionType
+	case t.Kind() == reflect.Bool:
+		opt.Type = BooleanOptionType
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		opt.Type = IntegerOptionType
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		opt.Type = FloatOptionType
+	default:
+		return opt, fmt.Errorf("unsupported type %v", field.Type)
+	}
+
+	for _, bound := range []string{"min", "max"} {
+		tag, ok := field.Tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+
+		rule, err := typedBoundRule(opt, bound, tag)
+		if err != nil {
+			return opt, err
+		}
+		opt.Rules = append(opt.Rules, rule)
+	}
+
+	if tag := field.Tag.Get("choices"); tag != "" {
+		for _, choice := range strings.Split(tag, ",") {
+			name, value, ok := strings.Cut(choice, "=")
+			if !ok {
+				value = name
+			}
+
+			v, err := parseTypedValue(opt.Type, value)
+			if err != nil {
+				return opt, fmt.Errorf("invalid choice '%s': %w", choice, err)
+			}
+			opt.Choices = append(opt.Choices, Choice{Name: name, Value: v})
+		}
+	}
+
+	return opt, nil
+}
+
+func typedBoundRule(opt Option, bound string, tag string) (Rule, error) {
+	if opt.Variadic {
+		n, err := strconv.Atoi(tag)
+		if bound == "min" {
+			return MinItems{n}, err
+		}
+		return MaxItems{n}, err
+	}
+
+	switch opt.Type {
+	case IntegerOptionType:
+		n, err := strconv.ParseInt(tag, 10, 64)
+		if bound == "min" {
+			return MinInt{n}, err
+		}
+		return MaxInt{n}, err
+	case FloatOptionType:
+		n, err := strconv.ParseFloat(tag, 64)
+		if bound == "min" {
+			return MinFloat{n}, err
+		}
+		return MaxFloat{n}, err
+	case StringOptionType:
+		n, err := strconv.Atoi(tag)
+		if bound == "min" {
+			return MinString{n}, err
+		}
+		return MaxString{n}, err
+	default:
+		return nil, fmt.Errorf("%s is not supported for this option type", bound)
+	}
+}
+
+func parseTypedValue(t OptionType, value string) (any, error) {
+	switch t {
+	case IntegerOptionType:
+		return strconv.ParseInt(value, 10, 64)
+	case FloatOptionType:
+		return strconv.ParseFloat(value, 64)
+	case StringOptionType:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("choices are not supported for this option type")
+	}
+}
+
+func assignTyped(field reflect.Value, value any) error {
+	v := reflect.ValueOf(value)
+
+	if field.Kind() == reflect.Slice && v.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := assignTyped(slice.Index(i), v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	if !v.Type().ConvertibleTo(field.Type()) {
+		return fmt.Errorf("cannot assign %v to %v", v.Type(), field.Type())
+	}
+	field.Set(v.Convert(field.Type()))
+	return nil
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}